@@ -1,5 +1,7 @@
 package browserk
 
+import "encoding/json"
+
 // ActionType defines the action type for a browser action
 type ActionType int8
 
@@ -56,6 +58,34 @@ var ActionTypeMap = map[ActionType]string{
 	ActSubRequest: "ActSubRequest",
 }
 
+// KeyModifier bit field of the modifier keys held during a keyboard action
+type KeyModifier int
+
+// Modifier values match the devtools protocol
+const (
+	ModifierAlt   KeyModifier = 1
+	ModifierCtrl  KeyModifier = 2
+	ModifierMeta  KeyModifier = 4
+	ModifierShift KeyModifier = 8
+)
+
+// KeyboardInput is the Input of ActSendKeys, ActKeyDown and ActKeyUp actions. Keys is text
+// and/or the special keys of the keymap package (Enter, ArrowDown etc)
+type KeyboardInput struct {
+	Keys      string      `json:"keys"`
+	Modifiers KeyModifier `json:"modifiers,omitempty"`
+}
+
+// NewKeyboardAction for sending keys to the element
+func NewKeyboardAction(aType ActionType, ele *HTMLElement, input *KeyboardInput) *Action {
+	encoded, _ := json.Marshal(input)
+	return &Action{
+		Type:    aType,
+		Input:   encoded,
+		Element: ele,
+	}
+}
+
 // Action runs a browser action, may or may not create a result
 type Action struct {
 	browser Browser
@@ -73,6 +103,20 @@ func NewLoadURLAction(url string) *Action {
 	}
 }
 
+// KeyboardInput of a keyboard action created by NewKeyboardAction. Returns an empty
+// KeyboardInput if there is no input or it was not encoded as a KeyboardInput
+func (a *Action) KeyboardInput() *KeyboardInput {
+	input := &KeyboardInput{}
+	if len(a.Input) == 0 {
+		return input
+	}
+
+	if err := json.Unmarshal(a.Input, input); err != nil {
+		return &KeyboardInput{}
+	}
+	return input
+}
+
 func (a *Action) String() string {
 	ret := ""
 	switch a.Type {
@@ -84,6 +128,8 @@ func (a *Action) String() string {
 			ret += k + "=" + v
 		}
 		ret += "]"
+	case ActSendKeys, ActKeyDown, ActKeyUp:
+		ret += "[" + HTMLTypeToStrMap[a.Element.Type] + " keys=" + a.KeyboardInput().Keys + "]"
 	case ActFillForm:
 		ret += "[ FORM "
 		for k, v := range a.Form.Attributes {
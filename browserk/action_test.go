@@ -0,0 +1,40 @@
+package browserk_test
+
+import (
+	"bytes"
+	"testing"
+
+	"gitlab.com/browserker/browserk"
+)
+
+func TestKeyboardInput(t *testing.T) {
+	ele := &browserk.HTMLElement{Type: browserk.INPUT, Attributes: map[string]string{"name": "q"}}
+	act := browserk.NewKeyboardAction(browserk.ActSendKeys, ele, &browserk.KeyboardInput{Keys: "a", Modifiers: browserk.ModifierCtrl | browserk.ModifierShift})
+	input := act.KeyboardInput()
+	if input.Keys != "a" || input.Modifiers != browserk.ModifierCtrl|browserk.ModifierShift {
+		t.Fatalf("expected ctrl+shift+a got %+v\n", input)
+	}
+
+	// input that isn't a KeyboardInput is never sent
+	act = &browserk.Action{Type: browserk.ActSendKeys, Input: []byte("search")}
+	if input := act.KeyboardInput(); input.Keys != "" || input.Modifiers != 0 {
+		t.Fatalf("expected no keys for raw input got %+v\n", input)
+	}
+
+	act = &browserk.Action{Type: browserk.ActSendKeys}
+	if input := act.KeyboardInput(); input.Keys != "" {
+		t.Fatalf("expected no keys got %+v\n", input)
+	}
+
+	from := browserk.NewNavigation(browserk.TrigInitial, browserk.NewLoadURLAction("http://example.com/"))
+	enter := browserk.NewNavigationFromElement(from, browserk.TrigCrawler, ele, browserk.ActSendKeys)
+	typed := browserk.NewNavigationFromKeyboard(from, browserk.TrigCrawler, ele, browserk.ActSendKeys, &browserk.KeyboardInput{Keys: "test"})
+	other := browserk.NewNavigationFromKeyboard(from, browserk.TrigCrawler, ele, browserk.ActSendKeys, &browserk.KeyboardInput{Keys: "other"})
+	if bytes.Equal(enter.ID, typed.ID) || bytes.Equal(typed.ID, other.ID) {
+		t.Fatalf("expected keyboard input to be part of the navigation id\n")
+	}
+
+	if typed.Action.KeyboardInput().Keys != "test" || typed.Distance != 1 || !bytes.Equal(typed.OriginID, from.ID) {
+		t.Fatalf("unexpected keyboard navigation %+v\n", typed)
+	}
+}
@@ -1,8 +1,22 @@
 package browserk
 
+import "time"
+
 // AuthService handles logging in and checking login state throughout a scan/crawl
 type AuthService interface {
 	Init() error
-	Login(c *Context)
+	Login(c *Context, browser Browser) error
 	MustLogin() bool
+	Session() *Session
+	RestoreSession(c *Context, browser Browser) error
+	IsLoggedOut(c *Context, browser Browser, nav *Navigation, result *NavigationResult) bool
+	HTTPCredentials(c *Context, challenge *AuthChallengeEvent) (username, password string, ok bool)
+}
+
+// Session captures the browser state after a successful login so
+// every newly leased browser can start from an authenticated state
+type Session struct {
+	Cookies  []*Cookie `json:"cookies"`  // cookies set after logging in
+	URL      string    `json:"url"`      // url the browser was on after logging in
+	Observed time.Time `json:"observed"` // time the session was captured
 }
@@ -22,9 +22,14 @@ type Browser interface {
 	GetURL() (string, error)
 	GetDOM() (string, error)
 	GetCookies() ([]*Cookie, error)
+	SetCookies(cookies []*Cookie) error
 	GetBaseHref() string
 	GetStorageEvents() []*StorageEvent
 	GetConsoleEvents() []*ConsoleEvent
+	GetBlockedRequests() []*BlockedRequestEvent
+	GetAuthChallenges() []*AuthChallengeEvent
+	GetURLChanges() []*URLChangeEvent
+	GetScripts() []*ScriptEvent // scripts parsed since the last call, with their source
 	Navigate(ctx context.Context, url string) (err error)
 	FindElements(querySelector string) ([]*HTMLElement, error)
 	FindForms() ([]*HTMLFormElement, error)
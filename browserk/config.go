@@ -2,9 +2,19 @@ package browserk
 
 // Credentials for logging into a target site
 type Credentials struct {
-	Username string
-	Password string
-	Email    string
+	Username   string
+	Password   string
+	Email      string
+	TOTPSecret string // base32 encoded secret used to generate one time codes
+	Domain     string // windows domain sent with the username for NTLM challenges
+}
+
+// Identity is a named user we login as, used to replay another identity's crawl
+// for access control checks
+type Identity struct {
+	Name        string
+	Credentials *Credentials
+	AuthHeaders map[string]string // overrides Config.AuthHeaders for header based auth
 }
 
 // AuthType defines how we are going to authenticate
@@ -15,8 +25,35 @@ const (
 	Script AuthType = iota
 	// Raw POST / whatever
 	Raw
+	// Form finds the login form and fills it with Credentials
+	Form
+	// Header adds AuthHeaders to every in scope request
+	Header
+	// OAuth2 acquires tokens from an OAuth2/OIDC provider and adds them to every in scope request
+	OAuth2
+	// NoAuth never authenticates, not even to basic/digest/ntlm challenges
+	NoAuth
 )
 
+// OAuth2 grant types
+const (
+	GrantClientCredentials = "client_credentials"
+	GrantPassword          = "password"
+	GrantAuthorizationCode = "authorization_code"
+)
+
+// OAuthConfig for acquiring tokens from an OAuth2/OIDC provider
+type OAuthConfig struct {
+	Grant           string   // one of the Grant types
+	AuthorizeURL    string   // authorization endpoint (authorization_code only)
+	TokenURL        string   // token endpoint
+	RedirectURL     string   // registered redirect uri (authorization_code only)
+	ClientID        string   // client id
+	ClientSecret    string   // client secret, not required for public clients using PKCE
+	Scopes          []string // scopes to request
+	ConsentSelector string   // css selector of the button that grants consent, if the provider asks
+}
+
 type FormData struct {
 	// Name/User related
 	UserName      string
@@ -112,19 +149,39 @@ var DefaultFormValues = FormData{
 
 // Config for browserker
 type Config struct {
-	URL             string
-	AllowedHosts    []string // considered 'in scope' for testing/access
-	IgnoredHosts    []string // will access, but not report/run tests against (this is the default for non AllowedURLs)
-	ExcludedHosts   []string // will be forcibly dropped by interceptors
-	ExcludedURIs    []string // will not access (logout/signout) can be relative, or absolute (relative will be from config URL base path)
-	ExcludedForms   []string // will not submit forms that have this id or name
-	DataPath        string
-	AuthScript      string
-	AuthType        AuthType
-	Credentials     *Credentials
-	NumBrowsers     int
-	MaxDepth        int       // maximum distance of paths we will traverse
-	FormData        *FormData // config form data
-	JSPluginPath    string    // path to javascript plugins (will walk sub directories)
-	DisabledPlugins []string  // plugins we will not load
+	URL               string
+	AllowedHosts      []string // considered 'in scope' for testing/access, hosts may have wildcards (*.example.com), ports, schemes, path globs or be re: regexes
+	IgnoredHosts      []string // will access, but not report/run tests against (this is the default for non AllowedURLs), ExcludedURIs take precedence
+	ExcludedHosts     []string // will be forcibly dropped by interceptors, takes precedence over IgnoredHosts, ExcludedURIs and AllowedHosts
+	ExcludedURIs      []string // will not access (logout/signout) can be relative paths/globs for any host, absolute, or re: regexes, optionally with a METHOD prefix and ?param=value
+	ExcludedForms     []string // will not submit forms that have this id or name, action:<uri rule> or field:<glob> of a contained field
+	DataPath          string
+	AuthScript        string
+	AuthType          AuthType
+	Credentials       *Credentials
+	LoginURL          string                  // url of the login page (defaults to URL)
+	LoggedInElement   string                  // css selector of an element that only exists when logged in
+	LoggedInCookie    string                  // name of a cookie that only exists when logged in
+	LoggedOutRegex    string                  // regex matching the body of pages we only see when logged out
+	AuthHeaders       map[string]string       // headers added to in scope requests, {token} is replaced with the current token
+	TokenCommand      string                  // command whose output is used as the token
+	TokenURL          string                  // endpoint returning the token, either raw or as json access_token/token
+	TokenExpiry       int                     // seconds a token is valid for if the endpoint does not tell us (0 never expires)
+	OAuth             *OAuthConfig            // provider settings for OAuth2 auth
+	Identities        []*Identity             // the first identity crawls, the others (and no identity) replay its paths
+	StateFile         string                  // netscape cookie jar or playwright storageState json applied to the crawling identity's browsers
+	HTTPCredentials   map[string]*Credentials // basic/digest/ntlm credentials by host, in scope hosts default to Credentials
+	DisableSeeding    bool                    // don't seed the crawl from robots.txt, sitemap.xml and security.txt
+	NumBrowsers       int
+	MaxDepth          int       // maximum distance of paths we will traverse
+	MaxNavigations    int       // maximum number of navigation paths to crawl (0 no limit)
+	MaxPerPathPattern int       // maximum navigations per url path pattern, ids in paths are ignored (0 no limit)
+	MaxDuration       int       // maximum seconds to crawl for (0 no limit)
+	MaxRetries        int       // times a failed navigation is retried before giving up (0 never retry)
+	RetryBackoff      int       // seconds to wait before the first retry, doubled for each failure (defaults to 5)
+	StaleTimeout      int       // seconds a navigation may be in process before it's reset to unvisited (defaults to 600)
+	MaxScrolls        int       // times a page is scrolled looking for lazy loaded content (defaults to 10, negative disables)
+	FormData          *FormData // config form data
+	JSPluginPath      string    // path to javascript plugins (will walk sub directories)
+	DisabledPlugins   []string  // plugins we will not load
 }
@@ -33,6 +33,7 @@ type Context struct {
 	Injector       Injector
 	Crawl          CrawlGrapher
 	PluginServicer PluginServicer
+	State          *BrowserState // cookies and storage applied to browsers taken with this context
 
 	jsBeforeHandler []JSHandler
 	jsBeforeIndex   int8
@@ -54,13 +55,16 @@ type Context struct {
 func (c *Context) Copy() *Context {
 	return &Context{
 		Ctx:             c.Ctx,
+		Log:             c.Log,
 		CtxComplete:     c.CtxComplete,
+		Auth:            c.Auth,
 		Scope:           c.Scope,
 		FormHandler:     c.FormHandler,
 		Reporter:        c.Reporter,
 		Injector:        c.Injector,
 		Crawl:           c.Crawl,
 		PluginServicer:  c.PluginServicer,
+		State:           c.State,
 		jsBeforeHandler: c.jsBeforeHandler,
 		jsBeforeIndex:   0,
 		jsAfterHandler:  c.jsAfterHandler,
@@ -41,3 +41,17 @@ func DiffCookies(oldCookies, newCookies []*Cookie) []*Cookie {
 	// TODO do comparison
 	return newCookies
 }
+
+// OriginStorage is the local and session storage of a single origin
+type OriginStorage struct {
+	Origin         string            `json:"origin"`
+	LocalStorage   map[string]string `json:"local_storage"`
+	SessionStorage map[string]string `json:"session_storage"`
+}
+
+// BrowserState captured outside of browserker (cookie jar, storageState) and applied
+// to every browser before it is used
+type BrowserState struct {
+	Cookies []*Cookie        `json:"cookies"`
+	Origins []*OriginStorage `json:"origins"`
+}
@@ -1,16 +1,24 @@
 package browserk
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // CrawlGrapher is a graph based storage system
 type CrawlGrapher interface {
 	Init() error
+	SetMaxDepth(depth int)
 	Close() error
 	Find(ctx context.Context, byState, setState NavState, limit int64) [][]*Navigation
 	AddNavigation(nav *Navigation) error
 	AddNavigations(navs []*Navigation) error
-	FailNavigation(navID []byte) error
+	FailNavigation(navID []byte, reason FailureReason, navErr error, maxRetries int) (NavState, error)
+	RetryNavigations(backoff func(failures int) time.Duration) (int, int)
+	ResetInProcess() int
+	ResetStaleNavigations(timeout time.Duration) [][]byte // returns the ids of the reset navigations
 	AddResult(result *NavigationResult) error
+	GetNavigationResult(navID []byte) (*NavigationResult, error)
 	NavExists(nav *Navigation) bool
 	GetNavigation(id []byte) (*Navigation, error)
 }
@@ -3,6 +3,7 @@ package browserk
 import (
 	"bytes"
 	"crypto/md5"
+	"io"
 	"sort"
 	"strings"
 )
@@ -26,7 +27,8 @@ type HTMLElement struct {
 	Hidden        bool
 	NodeDepth     int
 	ID            []byte
-	Value         string // value to set if it's an input field or whatever
+	Value         string   // value to set if it's an input field or whatever
+	Path          []string // frames/shadow roots the element is in (outermost first), empty for the top document
 }
 
 // Hash the element to (hopefully) a unique value
@@ -45,6 +47,7 @@ func (h *HTMLElement) Hash() []byte {
 	// include event line/col into the uniqueness
 	evts := sortEvents(h.Events)
 	hash.Write([]byte(evts))
+	writePath(hash, h.Path)
 	h.ID = hash.Sum(nil)
 	return h.ID
 }
@@ -107,6 +110,7 @@ type HTMLFormElement struct {
 	ChildElements  []*HTMLElement // capture all children (labels etc) so we can do context analysis
 	ID             []byte
 	SubmitButtonID []byte
+	Path           []string // frames/shadow roots the form is in (outermost first), empty for the top document
 }
 
 // Hash the form and it's input elements to (hopefully) a unique value
@@ -132,6 +136,7 @@ func (h *HTMLFormElement) Hash() []byte {
 	hash.Write([]byte(sorted))
 	evts := sortEvents(h.Events)
 	hash.Write([]byte(evts))
+	writePath(hash, h.Path)
 	h.ID = hash.Sum(nil)
 	return h.ID
 }
@@ -192,6 +197,38 @@ func (h *HTMLFormElement) GetChildByHash(hash []byte) *HTMLElement {
 	return nil
 }
 
+// Element path steps, the host element of a frame or shadow root is described by ElementPathHost
+const (
+	FramePathStep  = "frame:"
+	ShadowPathStep = "shadow:"
+)
+
+// ElementPathHost describes the host of a frame or shadow root for an element path by its
+// tag, id, name and (frame) src
+func ElementPathHost(tag string, attributes map[string]string) string {
+	host := strings.ToLower(tag)
+	if id := attributes["id"]; id != "" {
+		host += "#" + id
+	}
+
+	if name := attributes["name"]; name != "" {
+		host += "[name=" + name + "]"
+	}
+
+	if src := attributes["src"]; src != "" {
+		host += "[src=" + src + "]"
+	}
+	return host
+}
+
+// writePath into the hash so the same element in different frames/shadow roots is unique,
+// elements in the top document hash the same as they always have
+func writePath(hash io.Writer, path []string) {
+	for _, step := range path {
+		hash.Write([]byte(step))
+	}
+}
+
 func sortEvents(toSort map[string]HTMLEventType) string {
 	events := make([]string, len(toSort))
 	i := 0
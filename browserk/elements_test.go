@@ -0,0 +1,41 @@
+package browserk_test
+
+import (
+	"bytes"
+	"testing"
+
+	"gitlab.com/browserker/browserk"
+)
+
+func TestElementPathHash(t *testing.T) {
+	newButton := func(path []string) *browserk.HTMLElement {
+		return &browserk.HTMLElement{
+			Type:       browserk.BUTTON,
+			Attributes: map[string]string{"id": "save"},
+			InnerText:  "Save",
+			Path:       path,
+		}
+	}
+
+	top := newButton(nil).Hash()
+	if !bytes.Equal(top, newButton([]string{}).Hash()) {
+		t.Fatalf("empty path should hash the same as no path\n")
+	}
+
+	shadow := newButton([]string{browserk.ShadowPathStep + browserk.ElementPathHost("my-toolbar", nil)}).Hash()
+	frame := newButton([]string{browserk.FramePathStep + browserk.ElementPathHost("IFRAME", map[string]string{"src": "/editor"})}).Hash()
+	if bytes.Equal(top, shadow) || bytes.Equal(top, frame) || bytes.Equal(shadow, frame) {
+		t.Fatalf("expected elements in different frames/shadow roots to hash differently\n")
+	}
+
+	form := &browserk.HTMLFormElement{Attributes: map[string]string{"action": "/login"}}
+	formHash := form.Hash()
+	framedForm := &browserk.HTMLFormElement{Attributes: map[string]string{"action": "/login"}, Path: []string{"frame:iframe"}}
+	if bytes.Equal(formHash, framedForm.Hash()) {
+		t.Fatalf("expected forms in different frames to hash differently\n")
+	}
+
+	if host := browserk.ElementPathHost("IFRAME", map[string]string{"id": "x", "name": "y", "src": "/z"}); host != "iframe#x[name=y][src=/z]" {
+		t.Fatalf("unexpected host %s\n", host)
+	}
+}
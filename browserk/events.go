@@ -37,3 +37,40 @@ type ConsoleEvent struct {
 	Column   int       `json:"column,omitempty"` // Column number in the resource that generated this message (1-based).
 	Observed time.Time `json:"observed"`         // time the console event occurred
 }
+
+// AuthChallengeEvent captures a basic/digest/ntlm challenge the browser received
+type AuthChallengeEvent struct {
+	URL      string    `json:"url"`              // url of the request that was challenged
+	Source   string    `json:"source,omitempty"` // Server or Proxy
+	Origin   string    `json:"origin"`           // origin of the challenger
+	Scheme   string    `json:"scheme"`           // basic, digest, ntlm or negotiate
+	Realm    string    `json:"realm"`            // realm of the challenge, may be empty
+	Answered bool      `json:"answered"`         // if we provided credentials
+	Observed time.Time `json:"observed"`         // time the challenge occurred
+}
+
+// URLChangeEvent captures a client side route change of the top level document
+type URLChangeEvent struct {
+	Type     string    `json:"type"`     // pushState, replaceState, popstate or hashchange
+	URL      string    `json:"url"`      // location after the change
+	Observed time.Time `json:"observed"` // time the url changed
+}
+
+// ScriptEvent captures a script the browser parsed
+type ScriptEvent struct {
+	ScriptID string    `json:"script_id"` // debugger id of the script
+	URL      string    `json:"url"`       // url of the script, empty for inline scripts
+	Hash     string    `json:"hash"`      // content hash of the script
+	Length   int       `json:"length"`    // length of the script
+	Source   string    `json:"-"`         // source of the script
+	Observed time.Time `json:"observed"`  // time the script was parsed
+}
+
+// BlockedRequestEvent captures a request the browser tried to send that we failed
+type BlockedRequestEvent struct {
+	Method       string    `json:"method"`        // request method
+	URL          string    `json:"url"`           // request url
+	ResourceType string    `json:"resource_type"` // Document, XHR, Script etc
+	Reason       string    `json:"reason"`        // network error reason the request was failed with
+	Observed     time.Time `json:"observed"`      // time the request was blocked
+}
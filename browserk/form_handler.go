@@ -5,4 +5,5 @@ package browserk
 type FormHandler interface {
 	Init() error
 	Fill(form *HTMLFormElement)
+	Suggest(ele *HTMLElement) string // context sensitive text for a single input/textarea
 }
@@ -45,13 +45,17 @@ type InterceptedHTTPRequest struct {
 
 // HTTPModifiedRequest allow modifications
 type HTTPModifiedRequest struct {
-	RequestId string                     `json:"requestId"`          // An id the client received in requestPaused event.
-	Url       string                     `json:"url,omitempty"`      // If set, the request url will be modified in a way that's not observable by page.
-	Method    string                     `json:"method,omitempty"`   // If set, the request method is overridden.
-	PostData  string                     `json:"postData,omitempty"` // If set, overrides the post data in the request.
-	Headers   []*gcdapi.FetchHeaderEntry `json:"headers,omitempty"`  // If set, overrides the request headers.
+	RequestId  string                     `json:"requestId"`             // An id the client received in requestPaused event.
+	Url        string                     `json:"url,omitempty"`         // If set, the request url will be modified in a way that's not observable by page.
+	Method     string                     `json:"method,omitempty"`      // If set, the request method is overridden.
+	PostData   string                     `json:"postData,omitempty"`    // If set, overrides the post data in the request.
+	Headers    []*gcdapi.FetchHeaderEntry `json:"headers,omitempty"`     // If set, overrides the request headers.
+	FailReason string                     `json:"errorReason,omitempty"` // If set, the request is failed with this network error reason instead of being sent.
 }
 
+// BlockedByClient is the network error reason for requests we refused to send
+const BlockedByClient = "BlockedByClient"
+
 // InterceptedHTTPResponse to pass to middleware and allow modifications to Modified
 type InterceptedHTTPResponse struct {
 	RequestId           string                     `json:"requestId"`                     // Each request the page makes will have a unique id.
@@ -36,18 +36,50 @@ const (
 	NavVisited
 	// NavFailed unable to complete action
 	NavFailed
+	// NavSkipped excluded by scope rules, recorded but never visited
+	NavSkipped
+	// NavRetry failed but will be set to unvisited again after a backoff
+	NavRetry
 )
 
+// FailureReason categorizes why a navigation failed
+type FailureReason int8
+
+const (
+	// FailUnknown any other error
+	FailUnknown FailureReason = iota
+	// FailTimeout the page or action timed out
+	FailTimeout
+	// FailElementNotFound the element to act on could not be found
+	FailElementNotFound
+	// FailBrowserCrash the browser or tab crashed, or we could not get one
+	FailBrowserCrash
+	// FailLoggedOut we were still logged out after logging in again
+	FailLoggedOut
+)
+
+// FailureReasonMap for printing failure reasons
+var FailureReasonMap = map[FailureReason]string{
+	FailUnknown:         "unknown error",
+	FailTimeout:         "timed out",
+	FailElementNotFound: "element not found",
+	FailBrowserCrash:    "browser crashed",
+	FailLoggedOut:       "logged out",
+}
+
 // Navigation for storing the action and results of navigating
 type Navigation struct {
-	ID               []byte      `graph:"id"`            // unique id of this navigation depending on type
-	OriginID         []byte      `graph:"origin"`        // where this navigation node originated from
-	TriggeredBy      TriggeredBy `graph:"trig_by"`       // update to plugin/crawler/manual whatever type
-	State            NavState    `graph:"state"`         // state of this navigation
-	StateUpdatedTime time.Time   `graph:"state_updated"` // when the state was updated (for timeouts)
-	Action           *Action     `graph:"action"`
-	Scope            Scope       `graph:"scope"`
-	Distance         int         `graph:"dist"`
+	ID               []byte        `graph:"id"`            // unique id of this navigation depending on type
+	OriginID         []byte        `graph:"origin"`        // where this navigation node originated from
+	TriggeredBy      TriggeredBy   `graph:"trig_by"`       // update to plugin/crawler/manual whatever type
+	State            NavState      `graph:"state"`         // state of this navigation
+	StateUpdatedTime time.Time     `graph:"state_updated"` // when the state was updated (for timeouts)
+	Action           *Action       `graph:"action"`
+	Scope            Scope         `graph:"scope"`
+	Distance         int           `graph:"dist"`
+	Failures         int           `graph:"failures"`    // number of times this navigation failed
+	FailReason       FailureReason `graph:"fail_reason"` // category of the last failure
+	LastError        string        `graph:"last_err"`    // error message of the last failure
 }
 
 // NewNavigation type
@@ -132,21 +164,68 @@ func NewNavigationFromElement(from *Navigation, triggeredBy TriggeredBy, ele *HT
 	return n
 }
 
+// NewNavigationFromKeyboard creates a new navigation entry that sends the input to the element
+// (type-ahead, search-as-you-type, keyboard shortcuts), the input is part of the id
+func NewNavigationFromKeyboard(from *Navigation, triggeredBy TriggeredBy, ele *HTMLElement, aType ActionType, input *KeyboardInput) *Navigation {
+	n := NewNavigationFromElement(from, triggeredBy, ele, aType)
+	n.Action = NewKeyboardAction(aType, ele, input)
+
+	h := md5.New()
+	h.Write(n.ID)
+	h.Write(n.Action.Input)
+	n.ID = h.Sum(nil)
+	return n
+}
+
+// NewNavigationFromScroll creates a navigation that scrolls the page down once more than from,
+// elements that only exist after scrolling are navigated to from it so replaying them scrolls first
+func NewNavigationFromScroll(from *Navigation, triggeredBy TriggeredBy) *Navigation {
+	n := &Navigation{
+		Action:           &Action{Type: ActScroll},
+		OriginID:         from.ID,
+		TriggeredBy:      triggeredBy,
+		State:            NavUnvisited,
+		StateUpdatedTime: time.Now(),
+		Scope:            InScope,
+		Distance:         from.Distance + 1,
+	}
+
+	h := md5.New()
+	h.Write(n.OriginID)
+	h.Write([]byte{byte(ActScroll)})
+	n.ID = h.Sum(nil)
+	return n
+}
+
+// NewNavigationFromURL creates a navigation that directly loads a url found while on another
+// navigation (client side routes etc), it has no origin so it is loaded without replaying a path
+func NewNavigationFromURL(from *Navigation, triggeredBy TriggeredBy, url string) *Navigation {
+	n := NewNavigation(triggeredBy, &Action{
+		Type:  ActLoadURL,
+		Input: []byte(url),
+	})
+	n.Distance = from.Distance + 1
+	return n
+}
+
 // NavigationResult captures result details about a navigation
 type NavigationResult struct {
-	ID            []byte          `graph:"r_id"`
-	NavigationID  []byte          `graph:"r_nav_id"`
-	DOM           string          `graph:"r_dom"`
-	StartURL      string          `graph:"r_start_url"`
-	EndURL        string          `graph:"r_end_url"`
-	MessageCount  int             `graph:"r_message_count"`
-	Messages      []*HTTPMessage  `graph:"r_messages"`
-	Cookies       []*Cookie       `graph:"r_cookies"`
-	ConsoleEvents []*ConsoleEvent `graph:"r_console"`
-	StorageEvents []*StorageEvent `graph:"r_storage"`
-	CausedLoad    bool            `graph:"r_caused_load"`
-	WasError      bool            `graph:"r_was_error"`
-	Errors        []error         `graph:"r_errors"`
+	ID              []byte                 `graph:"r_id"`
+	NavigationID    []byte                 `graph:"r_nav_id"`
+	DOM             string                 `graph:"r_dom"`
+	StartURL        string                 `graph:"r_start_url"`
+	EndURL          string                 `graph:"r_end_url"`
+	MessageCount    int                    `graph:"r_message_count"`
+	Messages        []*HTTPMessage         `graph:"r_messages"`
+	Cookies         []*Cookie              `graph:"r_cookies"`
+	ConsoleEvents   []*ConsoleEvent        `graph:"r_console"`
+	StorageEvents   []*StorageEvent        `graph:"r_storage"`
+	BlockedRequests []*BlockedRequestEvent `graph:"r_blocked"`
+	AuthChallenges  []*AuthChallengeEvent  `graph:"r_auth_challenges"`
+	URLChanges      []*URLChangeEvent      `graph:"r_url_changes"`
+	CausedLoad      bool                   `graph:"r_caused_load"`
+	WasError        bool                   `graph:"r_was_error"`
+	Errors          []error                `graph:"r_errors"`
 }
 
 // Hash a unique ID for this result (needs work)
@@ -17,6 +17,7 @@ const (
 	EvtStorage
 	EvtCookie
 	EvtConsole
+	EvtAuthChallenge
 )
 
 type PluginEvent struct {
@@ -35,6 +36,8 @@ type PluginEventData struct {
 	Storage                 *StorageEvent
 	Cookie                  *Cookie
 	Console                 *ConsoleEvent
+	AuthChallenge           *AuthChallengeEvent
+	URLChange               *URLChangeEvent
 }
 
 func HTTPRequestPluginEvent(bctx *Context, URL string, nav *Navigation, request *HTTPRequest) *PluginEvent {
@@ -79,6 +82,18 @@ func ConsolePluginEvent(bctx *Context, URL string, nav *Navigation, console *Con
 	return evt
 }
 
+func AuthChallengePluginEvent(bctx *Context, URL string, nav *Navigation, challenge *AuthChallengeEvent) *PluginEvent {
+	evt := newPluginEvent(bctx, URL, nav, EvtAuthChallenge)
+	evt.EventData = &PluginEventData{AuthChallenge: challenge}
+	return evt
+}
+
+func URLChangePluginEvent(bctx *Context, URL string, nav *Navigation, change *URLChangeEvent) *PluginEvent {
+	evt := newPluginEvent(bctx, URL, nav, EvtURL)
+	evt.EventData = &PluginEventData{URLChange: change}
+	return evt
+}
+
 func newPluginEvent(bctx *Context, URL string, nav *Navigation, eventType PluginEventType) *PluginEvent {
 	return &PluginEvent{
 		Type: eventType,
@@ -1,12 +1,23 @@
 package browserk
 
-import "io"
+import (
+	"crypto/md5"
+	"fmt"
+	"io"
+)
 
+// Evidence of a finding
 type Evidence struct {
+	ID      []byte // uniquely identifies this instance of the finding (url, nav id etc)
+	Message string // human readable details
 }
 
+// Hash of the evidence ID so the same finding is only reported once
 func (e *Evidence) Hash() string {
-	return ""
+	if e == nil || e.ID == nil {
+		return ""
+	}
+	return fmt.Sprintf("%x", md5.Sum(e.ID))
 }
 
 type Report struct {
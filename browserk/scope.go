@@ -16,8 +16,22 @@ const (
 type ScopeService interface {
 	AddScope(inputs []string, scope Scope)
 	AddExcludedURIs(inputs []string)
-	ExcludeForms(idsOrNames []string)
+	ExcludeForms(rules []string)
+	CheckForm(baseHref string, form *HTMLFormElement) Scope
 	Check(uri string) Scope
+	CheckRequest(method, uri string) Scope
 	CheckRelative(base, relative string) Scope
 	ResolveBaseHref(baseHref, candidate string) Scope
 }
+
+// BlockExcludedRequests is the built-in RequestHandler browsers run before any other
+// request handler, it fails every request that is excluded from scope
+func BlockExcludedRequests(c *Context, browser Browser, i *InterceptedHTTPRequest) {
+	if c.Scope == nil || i.Request == nil || i.Request.Url == "" {
+		return
+	}
+
+	if c.Scope.CheckRequest(i.Request.Method, i.Request.Url) == ExcludedFromScope {
+		i.Modified.FailReason = BlockedByClient
+	}
+}
@@ -0,0 +1,50 @@
+package browserk
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TOTP defaults from RFC 6238
+const (
+	TOTPPeriod = 30
+	TOTPDigits = 6
+)
+
+// ErrNoTOTPSecret returned when generating a code without a secret
+var ErrNoTOTPSecret = errors.New("no totp secret configured")
+
+// TOTP generates the time based one time code for the credentials at time t
+func (c *Credentials) TOTP(t time.Time) (string, error) {
+	if c == nil || c.TOTPSecret == "" {
+		return "", ErrNoTOTPSecret
+	}
+	return GenerateTOTP(c.TOTPSecret, t)
+}
+
+// GenerateTOTP code (RFC 6238, HMAC-SHA1, 30 second period, 6 digits) from a base32 secret
+func GenerateTOTP(secret string, t time.Time) (string, error) {
+	secret = strings.ToUpper(strings.Replace(secret, " ", "", -1))
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(strings.TrimRight(secret, "="))
+	if err != nil {
+		return "", err
+	}
+
+	counter := make([]byte, 8)
+	binary.BigEndian.PutUint64(counter, uint64(t.Unix()/TOTPPeriod))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter)
+	sum := mac.Sum(nil)
+
+	// dynamic truncation (RFC 4226 section 5.3)
+	offset := sum[len(sum)-1] & 0xf
+	code := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", TOTPDigits, code%1000000), nil
+}
@@ -0,0 +1,37 @@
+package browserk_test
+
+import (
+	"testing"
+	"time"
+
+	"gitlab.com/browserker/browserk"
+)
+
+func TestGenerateTOTP(t *testing.T) {
+	// RFC 6238 appendix B test secret "12345678901234567890", truncated to 6 digits
+	secret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+	var tests = []struct {
+		unix     int64
+		expected string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+
+	for _, tt := range tests {
+		code, err := browserk.GenerateTOTP(secret, time.Unix(tt.unix, 0))
+		if err != nil {
+			t.Fatalf("error generating code: %s\n", err)
+		}
+		if code != tt.expected {
+			t.Fatalf("expected %s at %d got %s\n", tt.expected, tt.unix, code)
+		}
+	}
+
+	creds := &browserk.Credentials{}
+	if _, err := creds.TOTP(time.Now()); err != browserk.ErrNoTOTPSecret {
+		t.Fatalf("expected no secret error got %v\n", err)
+	}
+}
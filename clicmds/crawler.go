@@ -18,6 +18,7 @@ import (
 	"github.com/urfave/cli/v2"
 	"gitlab.com/browserker/browserk"
 	"gitlab.com/browserker/scanner"
+	"gitlab.com/browserker/scanner/report"
 	"gitlab.com/browserker/store"
 )
 
@@ -38,6 +39,11 @@ func CrawlerFlags() []cli.Flag {
 			Usage: "data directory",
 			Value: "browserktmp",
 		},
+		&cli.BoolFlag{
+			Name:  "fresh",
+			Usage: "remove the data directory and start a new crawl instead of resuming",
+			Value: false,
+		},
 		&cli.BoolFlag{
 			Name:  "profile",
 			Usage: "enable to profile cpu/mem",
@@ -53,6 +59,20 @@ func CrawlerFlags() []cli.Flag {
 			Usage: "max depth of nav paths to traverse",
 			Value: 10,
 		},
+		&cli.IntFlag{
+			Name:  "maxretries",
+			Usage: "times a failed navigation is retried before giving up",
+			Value: 2,
+		},
+		&cli.IntFlag{
+			Name:  "maxscrolls",
+			Usage: "times a page is scrolled looking for lazy loaded content (-1 disables)",
+			Value: 10,
+		},
+		&cli.StringFlag{
+			Name:  "state",
+			Usage: "netscape cookie jar or playwright storageState json to load into every browser",
+		},
 		&cli.BoolFlag{
 			Name:  "summary",
 			Usage: "print summary of urls/graph actions taken",
@@ -77,6 +97,9 @@ func Crawler(cliCtx *cli.Context) error {
 			URL:         cliCtx.String("url"),
 			NumBrowsers: cliCtx.Int("numbrowsers"),
 			MaxDepth:    cliCtx.Int("maxdepth"),
+			DataPath:    cliCtx.String("datadir"),
+			MaxRetries:  cliCtx.Int("maxretries"),
+			MaxScrolls:  cliCtx.Int("maxscrolls"),
 		}
 	} else {
 		data, err := ioutil.ReadFile(cliCtx.String("config"))
@@ -95,10 +118,17 @@ func Crawler(cliCtx *cli.Context) error {
 			cfg.DataPath = cliCtx.String("datadir")
 		}
 	}
-	os.RemoveAll(cfg.DataPath)
+
+	if cfg.StateFile == "" && cliCtx.String("state") != "" {
+		cfg.StateFile = cliCtx.String("state")
+	}
+	if err := prepareDataPath(cfg.DataPath, cliCtx.Bool("fresh")); err != nil {
+		return err
+	}
 	crawl := store.NewCrawlGraph(cfg.DataPath + "/crawl")
 	pluginStore := store.NewPluginStore(cfg.DataPath + "/plugin")
-	browserk := scanner.New(cfg, crawl, pluginStore)
+	reporter := report.New()
+	browserk := scanner.New(cfg, crawl, pluginStore).SetReporter(reporter)
 	log.Logger.Info().Msg("Starting browserker")
 
 	scanContext := context.Background()
@@ -107,17 +137,13 @@ func Crawler(cliCtx *cli.Context) error {
 		return err
 	}
 
-	c := make(chan os.Signal)
+	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
 	go func() {
 		<-c
-		log.Info().Msg("Ctrl-C Pressed, shutting down")
-		err := browserk.Stop()
-		log.Info().Msg("Giving a few seconds to sync db...")
-		time.Sleep(time.Second * 10)
-		if err != nil {
-			log.Error().Err(err).Msg("failed to stop browserk")
-		}
+		log.Info().Msg("Ctrl-C Pressed, shutting down (press again to force)")
+		browserk.Cancel()
+		<-c
 		os.Exit(1)
 	}()
 
@@ -126,13 +152,31 @@ func Crawler(cliCtx *cli.Context) error {
 		log.Error().Err(err).Msg("browserk failure occurred")
 	}
 
+	stats := browserk.Stats()
+	fmt.Printf("Crawled %d paths (%d failed) in %s\n", stats.Crawled, stats.Failed, stats.Duration.Round(time.Second))
+
 	if cliCtx.Bool("summary") {
 		printSummary(crawl)
+		reporter.Print(os.Stdout)
 	}
 
 	return browserk.Stop()
 }
 
+// prepareDataPath removes the data directory for fresh crawls, otherwise the existing
+// crawl graph and plugin store are reopened so we continue where we stopped
+func prepareDataPath(dataPath string, fresh bool) error {
+	if fresh {
+		log.Info().Str("datadir", dataPath).Msg("removing data directory for a fresh crawl")
+		return os.RemoveAll(dataPath)
+	}
+
+	if _, err := os.Stat(dataPath + "/crawl"); err == nil {
+		log.Info().Str("datadir", dataPath).Msg("resuming crawl from existing data directory (use --fresh to start over)")
+	}
+	return nil
+}
+
 func printSummary(crawl *store.CrawlGraph) error {
 	results, err := crawl.GetNavigationResults()
 	if err != nil {
@@ -143,7 +187,17 @@ func printSummary(crawl *store.CrawlGraph) error {
 		return fmt.Errorf("No result entries found")
 	}
 	fmt.Printf("Had %d results\n", len(results))
+	blocked := 0
 	for _, entry := range results {
+		for _, b := range entry.BlockedRequests {
+			fmt.Printf("URL blocked: %s %s (%s)\n", b.Method, b.URL, b.Reason)
+		}
+		blocked += len(entry.BlockedRequests)
+
+		for _, change := range entry.URLChanges {
+			fmt.Printf("Client side route: (%s) %s\n", change.Type, change.URL)
+		}
+
 		if entry.Messages != nil {
 			for _, m := range entry.Messages {
 				if m.Request == nil {
@@ -154,17 +208,37 @@ func printSummary(crawl *store.CrawlGraph) error {
 		}
 	}
 
+	fmt.Printf("Blocked %d requests to excluded systems\n", blocked)
+
 	entries := crawl.Find(nil, browserk.NavVisited, browserk.NavVisited, 999)
 	printEntries(entries, "visited")
 	entries = crawl.Find(nil, browserk.NavUnvisited, browserk.NavUnvisited, 999)
 	printEntries(entries, "unvisited")
 	entries = crawl.Find(nil, browserk.NavInProcess, browserk.NavInProcess, 999)
 	printEntries(entries, "in process")
-	entries = crawl.Find(nil, browserk.NavInProcess, browserk.NavInProcess, 999)
+	entries = crawl.Find(nil, browserk.NavRetry, browserk.NavRetry, 999)
+	printEntries(entries, "waiting for retry")
+	printFailures(entries)
+	entries = crawl.Find(nil, browserk.NavFailed, browserk.NavFailed, 999)
 	printEntries(entries, "nav failed")
+	printFailures(entries)
 	return nil
 }
 
+// printFailures prints why the final navigation of each path was never visited
+func printFailures(entries [][]*browserk.Navigation) {
+	reasons := make(map[browserk.FailureReason]int)
+	for _, paths := range entries {
+		nav := paths[len(paths)-1]
+		reasons[nav.FailReason]++
+		fmt.Printf("Failed %d times (%s): %s %s: %s\n", nav.Failures, browserk.FailureReasonMap[nav.FailReason], browserk.ActionTypeMap[nav.Action.Type], nav.Action, nav.LastError)
+	}
+
+	for reason, count := range reasons {
+		fmt.Printf("%d navigations failed due to: %s\n", count, browserk.FailureReasonMap[reason])
+	}
+}
+
 func printEntries(entries [][]*browserk.Navigation, navType string) {
 	fmt.Printf("Had %d %s entries\n", len(entries), navType)
 	for _, paths := range entries {
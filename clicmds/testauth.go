@@ -1,6 +1,22 @@
 package clicmds
 
-import "github.com/urfave/cli/v2"
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/pelletier/go-toml"
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v2"
+	"gitlab.com/browserker/browserk"
+	"gitlab.com/browserker/scanner/auth"
+	"gitlab.com/browserker/scanner/browser"
+	"gitlab.com/browserker/scanner/plugin"
+	"gitlab.com/browserker/store"
+)
 
 func TestAuthFlags() []cli.Flag {
 	return []cli.Flag{
@@ -19,9 +35,167 @@ func TestAuthFlags() []cli.Flag {
 			Usage: "url to authenticate to",
 			Value: "http://localhost/login",
 		},
+		&cli.StringFlag{
+			Name:  "config",
+			Usage: "config to use, flags override its credentials and login url",
+			Value: "",
+		},
+		&cli.StringFlag{
+			Name:  "script",
+			Usage: "login script to run instead of filling the login form",
+			Value: "",
+		},
+		&cli.StringFlag{
+			Name:  "screenshot",
+			Usage: "file to write a png screenshot to after logging in",
+			Value: "",
+		},
 	}
 }
 
-func TestAuth(ctx *cli.Context) error {
+// TestAuth runs the configured authentication flow in a single browser and prints the
+// resulting session
+func TestAuth(cliCtx *cli.Context) error {
+	cfg, err := testAuthConfig(cliCtx)
+	if err != nil {
+		return err
+	}
+
+	dataPath, err := ioutil.TempDir("", "browserktestauth")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dataPath)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	authService := auth.New(cfg)
+	if err := authService.Init(); err != nil {
+		return err
+	}
+
+	if !authService.MustLogin() {
+		return fmt.Errorf("no authentication configured")
+	}
+
+	pluginStore := store.NewPluginStore(dataPath)
+	if err := pluginStore.Init(); err != nil {
+		return err
+	}
+	defer pluginStore.Close()
+
+	pluginService := plugin.New(cfg, pluginStore)
+	if err := pluginService.Init(ctx); err != nil {
+		return err
+	}
+
+	authCtx := &browserk.Context{
+		Ctx:            ctx,
+		Log:            &log.Logger,
+		CtxComplete:    cancel,
+		Auth:           authService,
+		PluginServicer: pluginService,
+	}
+	authService.AddHandlers(authCtx)
+
+	pool := browser.NewGCDBrowserPool(1, browser.NewLocalLeaser())
+	if err := pool.Init(); err != nil {
+		return err
+	}
+	defer pool.Shutdown()
+
+	b, port, err := pool.Take(authCtx)
+	if err != nil {
+		return err
+	}
+	defer pool.Return(ctx, port)
+	defer b.Close()
+
+	loginErr := authService.Login(authCtx, b)
+	if cliCtx.String("screenshot") != "" {
+		if err := writeScreenshot(b, cliCtx.String("screenshot")); err != nil {
+			log.Error().Err(err).Msg("failed to write screenshot")
+		}
+	}
+
+	if loginErr != nil {
+		currentURL, _ := b.GetURL()
+		fmt.Printf("Login failed on %s: %s\n", currentURL, loginErr)
+		return loginErr
+	}
+
+	session := authService.Session()
+	fmt.Printf("Login succeeded, ended on %s\n", session.URL)
+	fmt.Printf("Had %d session cookies\n", len(session.Cookies))
+	for _, cookie := range session.Cookies {
+		fmt.Printf("Cookie: %s=%s (domain: %s path: %s secure: %v httponly: %v)\n",
+			cookie.Name, cookie.Value, cookie.Domain, cookie.Path, cookie.Secure, cookie.HTTPOnly)
+	}
+
+	events := b.GetStorageEvents()
+	fmt.Printf("Had %d storage events\n", len(events))
+	for _, evt := range events {
+		storageType := "session"
+		if evt.IsLocalStorage {
+			storageType = "local"
+		}
+		fmt.Printf("Storage: (%s %s) %s=%s\n", storageType, evt.SecurityOrigin, evt.Key, evt.NewValue)
+	}
 	return nil
 }
+
+// testAuthConfig reads the optional config and applies the flags on top of it
+func testAuthConfig(cliCtx *cli.Context) (*browserk.Config, error) {
+	cfg := &browserk.Config{AuthType: browserk.Form}
+	if cliCtx.String("config") != "" {
+		data, err := ioutil.ReadFile(cliCtx.String("config"))
+		if err != nil {
+			return nil, err
+		}
+
+		if err := toml.NewDecoder(strings.NewReader(string(data))).Decode(cfg); err != nil {
+			return nil, err
+		}
+	}
+	cfg.NumBrowsers = 1
+	cfg.FormData = &browserk.DefaultFormValues
+
+	// only the flags that were given override the configured credentials
+	if cfg.Credentials == nil {
+		cfg.Credentials = &browserk.Credentials{
+			Username: cliCtx.String("user"),
+			Password: cliCtx.String("pass"),
+		}
+	}
+	if cliCtx.IsSet("user") {
+		cfg.Credentials.Username = cliCtx.String("user")
+	}
+	if cliCtx.IsSet("pass") {
+		cfg.Credentials.Password = cliCtx.String("pass")
+	}
+
+	if cfg.URL == "" || cliCtx.IsSet("url") {
+		cfg.URL = cliCtx.String("url")
+		cfg.LoginURL = cliCtx.String("url")
+	}
+
+	if cliCtx.String("script") != "" {
+		cfg.AuthType = browserk.Script
+		cfg.AuthScript = cliCtx.String("script")
+	}
+	return cfg, nil
+}
+
+func writeScreenshot(b browserk.Browser, fileName string) error {
+	encoded, err := b.Screenshot()
+	if err != nil {
+		return err
+	}
+
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(fileName, data, 0644)
+}
@@ -0,0 +1,242 @@
+package mock
+
+import (
+	"context"
+
+	"gitlab.com/browserker/browserk"
+)
+
+// Browser allows overriding any browser call, defaults to doing nothing
+type Browser struct {
+	IDFn     func() int64
+	IDCalled bool
+
+	GetURLFn     func() (string, error)
+	GetURLCalled bool
+
+	GetDOMFn     func() (string, error)
+	GetDOMCalled bool
+
+	GetCookiesFn     func() ([]*browserk.Cookie, error)
+	GetCookiesCalled bool
+
+	SetCookiesFn     func(cookies []*browserk.Cookie) error
+	SetCookiesCalled bool
+
+	GetBaseHrefFn     func() string
+	GetBaseHrefCalled bool
+
+	GetStorageEventsFn     func() []*browserk.StorageEvent
+	GetStorageEventsCalled bool
+
+	GetConsoleEventsFn     func() []*browserk.ConsoleEvent
+	GetConsoleEventsCalled bool
+
+	GetBlockedRequestsFn     func() []*browserk.BlockedRequestEvent
+	GetBlockedRequestsCalled bool
+
+	GetAuthChallengesFn     func() []*browserk.AuthChallengeEvent
+	GetAuthChallengesCalled bool
+
+	GetURLChangesFn     func() []*browserk.URLChangeEvent
+	GetURLChangesCalled bool
+
+	GetScriptsFn     func() []*browserk.ScriptEvent
+	GetScriptsCalled bool
+
+	NavigateFn     func(ctx context.Context, url string) error
+	NavigateCalled bool
+
+	FindElementsFn     func(querySelector string) ([]*browserk.HTMLElement, error)
+	FindElementsCalled bool
+
+	FindFormsFn     func() ([]*browserk.HTMLFormElement, error)
+	FindFormsCalled bool
+
+	FindInteractablesFn     func() ([]*browserk.HTMLElement, error)
+	FindInteractablesCalled bool
+
+	GetMessagesFn     func() ([]*browserk.HTTPMessage, error)
+	GetMessagesCalled bool
+
+	ScreenshotFn     func() (string, error)
+	ScreenshotCalled bool
+
+	RefreshDocumentFn     func()
+	RefreshDocumentCalled bool
+
+	ExecuteActionFn     func(ctx context.Context, act *browserk.Action) ([]byte, bool, error)
+	ExecuteActionCalled bool
+
+	CloseFn     func()
+	CloseCalled bool
+}
+
+func (b *Browser) ID() int64 {
+	b.IDCalled = true
+	return b.IDFn()
+}
+
+func (b *Browser) GetURL() (string, error) {
+	b.GetURLCalled = true
+	return b.GetURLFn()
+}
+
+func (b *Browser) GetDOM() (string, error) {
+	b.GetDOMCalled = true
+	return b.GetDOMFn()
+}
+
+func (b *Browser) GetCookies() ([]*browserk.Cookie, error) {
+	b.GetCookiesCalled = true
+	return b.GetCookiesFn()
+}
+
+func (b *Browser) SetCookies(cookies []*browserk.Cookie) error {
+	b.SetCookiesCalled = true
+	return b.SetCookiesFn(cookies)
+}
+
+func (b *Browser) GetBaseHref() string {
+	b.GetBaseHrefCalled = true
+	return b.GetBaseHrefFn()
+}
+
+func (b *Browser) GetStorageEvents() []*browserk.StorageEvent {
+	b.GetStorageEventsCalled = true
+	return b.GetStorageEventsFn()
+}
+
+func (b *Browser) GetConsoleEvents() []*browserk.ConsoleEvent {
+	b.GetConsoleEventsCalled = true
+	return b.GetConsoleEventsFn()
+}
+
+func (b *Browser) GetBlockedRequests() []*browserk.BlockedRequestEvent {
+	b.GetBlockedRequestsCalled = true
+	return b.GetBlockedRequestsFn()
+}
+
+func (b *Browser) GetAuthChallenges() []*browserk.AuthChallengeEvent {
+	b.GetAuthChallengesCalled = true
+	return b.GetAuthChallengesFn()
+}
+
+func (b *Browser) GetURLChanges() []*browserk.URLChangeEvent {
+	b.GetURLChangesCalled = true
+	return b.GetURLChangesFn()
+}
+
+func (b *Browser) GetScripts() []*browserk.ScriptEvent {
+	b.GetScriptsCalled = true
+	return b.GetScriptsFn()
+}
+
+func (b *Browser) Navigate(ctx context.Context, url string) error {
+	b.NavigateCalled = true
+	return b.NavigateFn(ctx, url)
+}
+
+func (b *Browser) FindElements(querySelector string) ([]*browserk.HTMLElement, error) {
+	b.FindElementsCalled = true
+	return b.FindElementsFn(querySelector)
+}
+
+func (b *Browser) FindForms() ([]*browserk.HTMLFormElement, error) {
+	b.FindFormsCalled = true
+	return b.FindFormsFn()
+}
+
+func (b *Browser) FindInteractables() ([]*browserk.HTMLElement, error) {
+	b.FindInteractablesCalled = true
+	return b.FindInteractablesFn()
+}
+
+func (b *Browser) GetMessages() ([]*browserk.HTTPMessage, error) {
+	b.GetMessagesCalled = true
+	return b.GetMessagesFn()
+}
+
+func (b *Browser) Screenshot() (string, error) {
+	b.ScreenshotCalled = true
+	return b.ScreenshotFn()
+}
+
+func (b *Browser) RefreshDocument() {
+	b.RefreshDocumentCalled = true
+	b.RefreshDocumentFn()
+}
+
+func (b *Browser) ExecuteAction(ctx context.Context, act *browserk.Action) ([]byte, bool, error) {
+	b.ExecuteActionCalled = true
+	return b.ExecuteActionFn(ctx, act)
+}
+
+func (b *Browser) Close() {
+	b.CloseCalled = true
+	b.CloseFn()
+}
+
+func MakeMockBrowser() *Browser {
+	b := &Browser{}
+	b.IDFn = func() int64 {
+		return 1
+	}
+	b.GetURLFn = func() (string, error) {
+		return "about:blank", nil
+	}
+	b.GetDOMFn = func() (string, error) {
+		return "<html></html>", nil
+	}
+	b.GetCookiesFn = func() ([]*browserk.Cookie, error) {
+		return make([]*browserk.Cookie, 0), nil
+	}
+	b.SetCookiesFn = func(cookies []*browserk.Cookie) error {
+		return nil
+	}
+	b.GetBaseHrefFn = func() string {
+		return ""
+	}
+	b.GetStorageEventsFn = func() []*browserk.StorageEvent {
+		return make([]*browserk.StorageEvent, 0)
+	}
+	b.GetConsoleEventsFn = func() []*browserk.ConsoleEvent {
+		return make([]*browserk.ConsoleEvent, 0)
+	}
+	b.GetBlockedRequestsFn = func() []*browserk.BlockedRequestEvent {
+		return make([]*browserk.BlockedRequestEvent, 0)
+	}
+	b.GetAuthChallengesFn = func() []*browserk.AuthChallengeEvent {
+		return make([]*browserk.AuthChallengeEvent, 0)
+	}
+	b.GetURLChangesFn = func() []*browserk.URLChangeEvent {
+		return make([]*browserk.URLChangeEvent, 0)
+	}
+	b.GetScriptsFn = func() []*browserk.ScriptEvent {
+		return make([]*browserk.ScriptEvent, 0)
+	}
+	b.NavigateFn = func(ctx context.Context, url string) error {
+		return nil
+	}
+	b.FindElementsFn = func(querySelector string) ([]*browserk.HTMLElement, error) {
+		return make([]*browserk.HTMLElement, 0), nil
+	}
+	b.FindFormsFn = func() ([]*browserk.HTMLFormElement, error) {
+		return make([]*browserk.HTMLFormElement, 0), nil
+	}
+	b.FindInteractablesFn = func() ([]*browserk.HTMLElement, error) {
+		return make([]*browserk.HTMLElement, 0), nil
+	}
+	b.GetMessagesFn = func() ([]*browserk.HTTPMessage, error) {
+		return make([]*browserk.HTTPMessage, 0), nil
+	}
+	b.ScreenshotFn = func() (string, error) {
+		return "", nil
+	}
+	b.RefreshDocumentFn = func() {}
+	b.ExecuteActionFn = func(ctx context.Context, act *browserk.Action) ([]byte, bool, error) {
+		return nil, false, nil
+	}
+	b.CloseFn = func() {}
+	return b
+}
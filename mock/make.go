@@ -55,6 +55,71 @@ func MakeMockAddressForm() *browserk.HTMLFormElement {
 	}
 }
 
+// MakeMockLoginForm for an example login form
+func MakeMockLoginForm() *browserk.HTMLFormElement {
+	children := make([]*browserk.HTMLElement, 0)
+	children = append(children, MakeMockInput("hidden", "csrf", ""))
+	children = append(children, MakeMockLabel("login", "Username"))
+	children = append(children, MakeMockInput("text", "login", ""))
+	children = append(children, MakeMockLabel("pass", "Password"))
+	children = append(children, MakeMockInput("password", "pass", ""))
+	children = append(children, MakeMockInput("text", "search", "Search..."))
+	children = append(children, MakeMockButton("submit", "Sign In"))
+
+	return &browserk.HTMLFormElement{
+		FormType: browserk.FormLogin,
+		Events:   nil,
+		Attributes: map[string]string{
+			"action": "/login",
+			"method": "POST",
+		},
+		Hidden:        false,
+		NodeDepth:     3,
+		ChildElements: children,
+		ID:            nil,
+	}
+}
+
+func MakeMockOneTimeCodeForm() *browserk.HTMLFormElement {
+	children := make([]*browserk.HTMLElement, 0)
+	children = append(children, MakeMockInput("hidden", "csrf", ""))
+	children = append(children, MakeMockLabel("mfa_code", "Authenticator code"))
+	code := MakeMockInput("text", "mfa_code", "123456")
+	code.Attributes["autocomplete"] = "one-time-code"
+	children = append(children, code)
+	children = append(children, MakeMockInput("text", "postal_code", ""))
+	children = append(children, MakeMockButton("submit", "Verify"))
+
+	return &browserk.HTMLFormElement{
+		FormType: browserk.FormLogin,
+		Events:   nil,
+		Attributes: map[string]string{
+			"action": "/mfa",
+			"method": "POST",
+		},
+		Hidden:        false,
+		NodeDepth:     3,
+		ChildElements: children,
+		ID:            nil,
+	}
+}
+
+func MakeMockButton(buttonType, text string) *browserk.HTMLElement {
+	return &browserk.HTMLElement{
+		Type:          browserk.BUTTON,
+		CustomTagName: "",
+		Events:        nil,
+		Attributes: map[string]string{
+			"type": buttonType,
+		},
+		InnerText: text,
+		Hidden:    false,
+		NodeDepth: 0,
+		ID:        nil,
+		Value:     "",
+	}
+}
+
 func MakeMockInput(inputType, name, placeholder string) *browserk.HTMLElement {
 	return &browserk.HTMLElement{
 		Type:          browserk.INPUT,
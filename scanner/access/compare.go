@@ -0,0 +1,38 @@
+package access
+
+// DefaultThreshold is how similar two responses must be to be considered the same resource
+const DefaultThreshold = 0.95
+
+// Response captured while replaying a request or navigation path as an identity
+type Response struct {
+	Status int    // status of the document or api request, 0 if unknown
+	URL    string // url we ended up on
+	Body   string // dom or response body
+}
+
+// Success returns true if the response was not denied
+func (r *Response) Success() bool {
+	if r == nil {
+		return false
+	}
+	return r.Status == 0 || (r.Status >= 200 && r.Status < 300)
+}
+
+// IsBroken returns true and the similarity when another identity received what the owner did,
+// while the anonymous (unauthenticated) replay did not. If anonymous got the same response the
+// resource is considered public.
+func IsBroken(owner, other, anonymous *Response, threshold float64) (bool, float64) {
+	if !owner.Success() || !other.Success() {
+		return false, 0
+	}
+
+	similarity := Similarity(owner.Body, other.Body)
+	if similarity < threshold {
+		return false, similarity
+	}
+
+	if anonymous.Success() && Similarity(owner.Body, anonymous.Body) >= threshold {
+		return false, similarity
+	}
+	return true, similarity
+}
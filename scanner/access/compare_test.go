@@ -0,0 +1,48 @@
+package access_test
+
+import (
+	"testing"
+
+	"gitlab.com/browserker/scanner/access"
+)
+
+const ownerProfile = `<html><body><h1>Profile</h1><p>name: alice</p><p>email: alice@example.com</p>
+<p>address: 1 main st</p><p>card ending 4242</p><a href="/logout">logout</a></body></html>`
+
+func TestSimilarity(t *testing.T) {
+	if s := access.Similarity(ownerProfile, ownerProfile); s != 1 {
+		t.Fatalf("expected identical bodies to be 1 got %v\n", s)
+	}
+
+	if s := access.Similarity(ownerProfile, "<html><body>please login</body></html>"); s > 0.1 {
+		t.Fatalf("expected different bodies to not be similar got %v\n", s)
+	}
+
+	if s := access.Similarity("", ownerProfile); s != 0 {
+		t.Fatalf("expected empty body to have no similarity got %v\n", s)
+	}
+}
+
+func TestIsBroken(t *testing.T) {
+	owner := &access.Response{Status: 200, Body: ownerProfile}
+	login := &access.Response{Status: 200, Body: "<html><body><form>username password</form></body></html>"}
+
+	var tests = []struct {
+		name      string
+		other     *access.Response
+		anonymous *access.Response
+		expected  bool
+	}{
+		{"other sees owner data", &access.Response{Status: 200, Body: ownerProfile}, login, true},
+		{"other denied", &access.Response{Status: 403, Body: ownerProfile}, login, false},
+		{"other sees own data", &access.Response{Status: 200, Body: "<h1>Profile</h1><p>name: bob</p><p>email: bob@example.com</p>"}, login, false},
+		{"public page", &access.Response{Status: 200, Body: ownerProfile}, &access.Response{Status: 200, Body: ownerProfile}, false},
+		{"anonymous not replayed", &access.Response{Status: 200, Body: ownerProfile}, nil, true},
+	}
+
+	for _, tt := range tests {
+		if broken, _ := access.IsBroken(owner, tt.other, tt.anonymous, access.DefaultThreshold); broken != tt.expected {
+			t.Fatalf("%s: expected %v got %v\n", tt.name, tt.expected, broken)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package access
+
+import (
+	"strings"
+	"unicode"
+)
+
+// shingleSize is the number of tokens per shingle
+const shingleSize = 3
+
+// Similarity of two response bodies between 0 (nothing in common) and 1 (identical)
+// using the jaccard index of their token shingles
+func Similarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+
+	sa := shingles(a)
+	sb := shingles(b)
+	if len(sa) == 0 || len(sb) == 0 {
+		return 0
+	}
+
+	intersection := 0
+	for shingle := range sa {
+		if _, ok := sb[shingle]; ok {
+			intersection++
+		}
+	}
+	union := len(sa) + len(sb) - intersection
+	return float64(intersection) / float64(union)
+}
+
+func shingles(body string) map[string]struct{} {
+	tokens := strings.FieldsFunc(body, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+
+	set := make(map[string]struct{})
+	if len(tokens) < shingleSize {
+		if len(tokens) > 0 {
+			set[strings.Join(tokens, " ")] = struct{}{}
+		}
+		return set
+	}
+
+	for i := 0; i <= len(tokens)-shingleSize; i++ {
+		set[strings.Join(tokens[i:i+shingleSize], " ")] = struct{}{}
+	}
+	return set
+}
@@ -0,0 +1,286 @@
+package scanner
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"gitlab.com/browserker/browserk"
+	"gitlab.com/browserker/scanner/access"
+	"gitlab.com/browserker/scanner/auth"
+	"gitlab.com/browserker/scanner/crawler"
+)
+
+const (
+	accessVulnID   = "BR-ACL-639"
+	accessCWE      = 639
+	maxAccessPaths = 1000
+)
+
+// replayRequestJS re-sends a captured api request from the page with a synchronous xhr
+// so it goes through the identity's cookies and request handlers
+const replayRequestJS = `(function() {
+	var xhr = new XMLHttpRequest();
+	xhr.open(%s, %s, false);
+	%s
+	xhr.send(%s);
+	return {status: xhr.status, url: xhr.responseURL, body: xhr.responseText};
+})()`
+
+// replayer is an identity (or no identity) we replay the crawled paths as
+type replayer struct {
+	name string
+	ctx  *browserk.Context
+	seen map[string]struct{} // api requests already replayed
+}
+
+// identityConfig returns a copy of the config that logs in as the identity, a nil
+// identity disables authentication and drops every credential
+func identityConfig(cfg *browserk.Config, identity *browserk.Identity) *browserk.Config {
+	identityCfg := *cfg
+	if identity == nil {
+		identityCfg.AuthType = browserk.NoAuth
+		identityCfg.AuthScript = ""
+		identityCfg.Credentials = nil
+		identityCfg.HTTPCredentials = nil
+		identityCfg.AuthHeaders = nil
+		identityCfg.OAuth = nil
+		return &identityCfg
+	}
+
+	if identity.Credentials != nil {
+		identityCfg.Credentials = identity.Credentials
+	}
+	if identity.AuthHeaders != nil {
+		identityCfg.AuthHeaders = identity.AuthHeaders
+	}
+	return &identityCfg
+}
+
+// newReplayer creates a context with its own auth service for the identity and logs in
+func (b *Browserk) newReplayer(identity *browserk.Identity) (*replayer, error) {
+	name := "unauthenticated"
+	if identity != nil {
+		name = identity.Name
+	}
+
+	c := &browserk.Context{
+		Ctx:            b.mainContext.Ctx,
+		Log:            b.mainContext.Log,
+		CtxComplete:    b.mainContext.CtxComplete,
+		Scope:          b.mainContext.Scope,
+		FormHandler:    b.mainContext.FormHandler,
+		Reporter:       b.mainContext.Reporter,
+		Injector:       b.mainContext.Injector,
+		Crawl:          b.mainContext.Crawl,
+		PluginServicer: b.mainContext.PluginServicer,
+	}
+
+	authService := auth.New(identityConfig(b.cfg, identity))
+	if err := authService.Init(); err != nil {
+		return nil, err
+	}
+	c.Auth = authService
+	authService.AddHandlers(c)
+
+	if authService.MustLogin() {
+		if err := b.login(c); err != nil {
+			return nil, err
+		}
+	}
+	return &replayer{name: name, ctx: c, seen: make(map[string]struct{})}, nil
+}
+
+// checkAccessControl replays every visited path of the crawling identity as the other
+// identities and unauthenticated, reporting resources the other identities should not see
+func (b *Browserk) checkAccessControl() {
+	if len(b.cfg.Identities) < 2 {
+		return
+	}
+	owner := b.cfg.Identities[0].Name
+
+	anonymous, err := b.newReplayer(nil)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to create unauthenticated replayer")
+		return
+	}
+
+	others := make([]*replayer, 0)
+	for _, identity := range b.cfg.Identities[1:] {
+		other, err := b.newReplayer(identity)
+		if err != nil {
+			log.Error().Err(err).Str("identity", identity.Name).Msg("failed to login as identity")
+			continue
+		}
+		others = append(others, other)
+	}
+
+	paths := b.crawlGraph.Find(b.mainContext.Ctx, browserk.NavVisited, browserk.NavVisited, maxAccessPaths)
+	log.Info().Int("paths", len(paths)).Int("identities", len(others)).Msg("checking access control")
+
+	for _, navs := range paths {
+		if b.mainContext.Ctx.Err() != nil {
+			return
+		}
+
+		last := navs[len(navs)-1]
+		result, err := b.crawlGraph.GetNavigationResult(last.ID)
+		if err != nil || result == nil || result.NavigationID == nil {
+			continue
+		}
+
+		ownerResponses := ownerResponses(result)
+		anonymousResponses := b.replay(anonymous, navs, result)
+		for _, other := range others {
+			otherResponses := b.replay(other, navs, result)
+			for key, otherResp := range otherResponses {
+				ownerResp, ok := ownerResponses[key]
+				if !ok {
+					continue
+				}
+
+				broken, similarity := access.IsBroken(ownerResp, otherResp, anonymousResponses[key], access.DefaultThreshold)
+				if !broken {
+					continue
+				}
+				b.reportAccess(owner, other.name, key, navs, similarity)
+			}
+		}
+	}
+}
+
+// replay the path as the identity, returning the responses for the final page and any api
+// requests it made keyed by method and url
+func (b *Browserk) replay(r *replayer, navs []*browserk.Navigation, result *browserk.NavigationResult) map[string]*access.Response {
+	responses := make(map[string]*access.Response)
+
+	browser, port, err := b.browsers.Take(r.ctx.Copy())
+	if err != nil {
+		log.Error().Err(err).Msg("failed to take browser for replay")
+		return responses
+	}
+	defer b.browsers.Return(r.ctx.Ctx, port)
+	defer browser.Close()
+
+	if r.ctx.Auth.MustLogin() {
+		if err := r.ctx.Auth.RestoreSession(r.ctx, browser); err != nil {
+			log.Error().Err(err).Str("identity", r.name).Msg("failed to restore session for replay")
+			return responses
+		}
+	}
+
+	replayCrawler := crawler.New(b.cfg)
+	var replayed *browserk.NavigationResult
+	for _, nav := range navs {
+		replayed, _, err = replayCrawler.Process(r.ctx, browser, nav, false)
+		if err != nil {
+			// the page is different for this identity, nothing to compare
+			log.Debug().Err(err).Str("identity", r.name).Msg("unable to replay path")
+			return responses
+		}
+	}
+	responses[pageKey(result)] = &access.Response{Status: documentStatus(replayed), URL: replayed.EndURL, Body: replayed.DOM}
+
+	for _, msg := range apiMessages(result) {
+		key := messageKey(msg)
+		if _, seen := r.seen[key]; seen {
+			continue
+		}
+		r.seen[key] = struct{}{}
+
+		out, _, err := browser.ExecuteAction(r.ctx.Ctx, &browserk.Action{Type: browserk.ActExecuteJS, Input: []byte(replayJS(msg))})
+		if err != nil {
+			continue
+		}
+
+		resp := &access.Response{}
+		if err := json.Unmarshal(out, resp); err != nil || resp.Status == 0 {
+			continue
+		}
+		responses[key] = resp
+	}
+	return responses
+}
+
+func (b *Browserk) reportAccess(owner, other, key string, navs []*browserk.Navigation, similarity float64) {
+	log.Warn().Str("owner", owner).Str("identity", other).Str("resource", key).Float64("similarity", similarity).Msg("possible broken access control")
+	b.reporter.Add(&browserk.Report{
+		VulnID:      accessVulnID,
+		CWE:         accessCWE,
+		Description: fmt.Sprintf("%s was able to access %s which belongs to %s", other, key, owner),
+		Remediation: "Verify the requesting user is authorized to access the resource on the server side",
+		Evidence: &browserk.Evidence{
+			ID:      []byte(other + key),
+			Message: fmt.Sprintf("path: %s similarity: %.2f", b.printActionStep(navs), similarity),
+		},
+	})
+}
+
+// ownerResponses from the crawling identity's stored result
+func ownerResponses(result *browserk.NavigationResult) map[string]*access.Response {
+	responses := make(map[string]*access.Response)
+	responses[pageKey(result)] = &access.Response{Status: documentStatus(result), URL: result.EndURL, Body: result.DOM}
+
+	for _, msg := range apiMessages(result) {
+		resp := &access.Response{Body: string(msg.Response.Body)}
+		if msg.Response.Response != nil {
+			resp.Status = msg.Response.Response.Status
+			resp.URL = msg.Response.Response.Url
+		}
+		responses[messageKey(msg)] = resp
+	}
+	return responses
+}
+
+// apiMessages returns the xhr/fetch requests that have a captured response
+func apiMessages(result *browserk.NavigationResult) []*browserk.HTTPMessage {
+	messages := make([]*browserk.HTTPMessage, 0)
+	for _, msg := range result.Messages {
+		if msg.Request == nil || msg.Request.Request == nil || msg.Response == nil {
+			continue
+		}
+		if msg.Request.Type == "XHR" || msg.Request.Type == "Fetch" {
+			messages = append(messages, msg)
+		}
+	}
+	return messages
+}
+
+// documentStatus of the last document request of the result
+func documentStatus(result *browserk.NavigationResult) int {
+	status := 0
+	for _, msg := range result.Messages {
+		if msg.Response != nil && msg.Response.Type == "Document" && msg.Response.Response != nil {
+			status = msg.Response.Response.Status
+		}
+	}
+	return status
+}
+
+func pageKey(result *browserk.NavigationResult) string {
+	return "PAGE " + result.EndURL
+}
+
+func messageKey(msg *browserk.HTTPMessage) string {
+	return msg.Request.Request.Method + " " + msg.Request.Request.Url
+}
+
+func replayJS(msg *browserk.HTTPMessage) string {
+	req := msg.Request.Request
+	method, _ := json.Marshal(req.Method)
+	url, _ := json.Marshal(req.Url)
+	body := []byte("null")
+	if req.PostData != "" {
+		body, _ = json.Marshal(req.PostData)
+	}
+
+	setHeader := ""
+	for name, value := range req.Headers {
+		if v, ok := value.(string); ok && strings.EqualFold(name, "content-type") {
+			contentType, _ := json.Marshal(v)
+			setHeader = fmt.Sprintf("xhr.setRequestHeader('Content-Type', %s);", contentType)
+		}
+	}
+	return fmt.Sprintf(replayRequestJS, method, url, setHeader, body)
+}
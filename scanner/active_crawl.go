@@ -0,0 +1,99 @@
+package scanner
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/rs/zerolog/log"
+	"gitlab.com/browserker/browserk"
+)
+
+// activeCrawl is a path being crawled, it is cancelled and released early if the navigation
+// it is crawling to goes stale
+type activeCrawl struct {
+	navID    []byte
+	ctx      context.Context
+	cancel   context.CancelFunc
+	stale    int32
+	lock     sync.Mutex
+	browser  browserk.Browser
+	port     string
+	released bool
+	release  sync.Once
+}
+
+// setBrowser returns false if the crawl was already released, the caller must close and return
+// the browser itself since releaseCrawl will not see it
+func (a *activeCrawl) setBrowser(browser browserk.Browser, port string) bool {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if a.released {
+		return false
+	}
+	a.browser = browser
+	a.port = port
+	return true
+}
+
+// takeBrowser marks the crawl released and returns the browser it holds, if any
+func (a *activeCrawl) takeBrowser() (browserk.Browser, string) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.released = true
+	return a.browser, a.port
+}
+
+// isStale returns true if the navigation was reset while we were crawling it, it may already be
+// crawled by another browser so nothing should be recorded for it
+func (a *activeCrawl) isStale() bool {
+	return atomic.LoadInt32(&a.stale) == 1
+}
+
+// startCrawl tracks the crawl to the navigation so it can be cancelled if it goes stale
+func (b *Browserk) startCrawl(navID []byte) *activeCrawl {
+	ctx, cancel := context.WithCancel(b.mainContext.Ctx)
+	active := &activeCrawl{navID: navID, ctx: ctx, cancel: cancel}
+
+	b.activeMutex.Lock()
+	b.activeCrawls[string(navID)] = active
+	b.activeMutex.Unlock()
+	return active
+}
+
+// releaseCrawl cancels the crawl, closes and returns its browser and marks it done. It's called
+// when the crawl finishes and when it goes stale, whichever is first
+func (b *Browserk) releaseCrawl(active *activeCrawl) {
+	active.release.Do(func() {
+		active.cancel()
+
+		b.activeMutex.Lock()
+		if b.activeCrawls[string(active.navID)] == active {
+			delete(b.activeCrawls, string(active.navID))
+		}
+		b.activeMutex.Unlock()
+
+		if browser, port := active.takeBrowser(); browser != nil {
+			b.removeLeased(browser.ID())
+			browser.Close()
+			b.browsers.Return(b.mainContext.Ctx, port)
+		}
+		b.crawlDone()
+	})
+}
+
+// cancelStale cancels and releases the crawls to navigations that were reset to unvisited
+func (b *Browserk) cancelStale(navIDs [][]byte) {
+	for _, navID := range navIDs {
+		b.activeMutex.Lock()
+		active, ok := b.activeCrawls[string(navID)]
+		b.activeMutex.Unlock()
+
+		if !ok {
+			continue
+		}
+		log.Warn().Str("nav_id", string(navID)).Msg("cancelling stale crawl")
+		atomic.StoreInt32(&active.stale, 1)
+		b.releaseCrawl(active)
+	}
+}
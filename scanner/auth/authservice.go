@@ -1,22 +1,203 @@
 package auth
 
-import "gitlab.com/browserker/browserk"
+import (
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"sync"
+	"time"
 
+	"github.com/dop251/goja"
+	"github.com/pkg/errors"
+	"gitlab.com/browserker/browserk"
+)
+
+// revive:exported
+var (
+	ErrUnsupportedAuthType = errors.New("unsupported authentication type")
+	ErrNoCredentials       = errors.New("no credentials configured")
+	ErrLoginFormNotFound   = errors.New("unable to find login form")
+	ErrLoginFailed         = errors.New("login did not succeed")
+	ErrNoTokenSource       = errors.New("auth headers use a token but no token command or url configured")
+	ErrEmptyToken          = errors.New("token was empty")
+	ErrTokenExpired        = errors.New("token expired and can not be refreshed")
+	ErrUnsupportedGrant    = errors.New("unsupported oauth2 grant type")
+	ErrStateMismatch       = errors.New("oauth2 state did not match")
+)
+
+// Service handles logging in and keeping the resulting session so
+// each browser can start from an authenticated state
 type Service struct {
+	cfg          *browserk.Config
+	sessionMutex *sync.RWMutex
+	session      *browserk.Session
+	loginTimeout time.Duration // how long to wait for the logged in indicator
+	loggedOutRe  *regexp.Regexp
+
+	client       *http.Client
+	tokenMutex   *sync.Mutex
+	token        string
+	refresh      string // oauth2 refresh token
+	tokenExpires time.Time
 }
 
+// New authentication service
 func New(cfg *browserk.Config) *Service {
-	return &Service{}
+	return &Service{
+		cfg:          cfg,
+		sessionMutex: &sync.RWMutex{},
+		loginTimeout: time.Second * 10,
+		client:       &http.Client{Timeout: time.Second * 30},
+		tokenMutex:   &sync.Mutex{},
+	}
 }
 
+// Init the service, validating the configuration
 func (s *Service) Init() error {
+	if !s.MustLogin() {
+		return nil
+	}
+
+	if s.cfg.LoggedOutRegex != "" {
+		re, err := regexp.Compile(s.cfg.LoggedOutRegex)
+		if err != nil {
+			return errors.Wrap(err, "compiling logged out regex")
+		}
+		s.loggedOutRe = re
+	}
+
+	switch s.cfg.AuthType {
+	case browserk.Script:
+		src, err := ioutil.ReadFile(s.cfg.AuthScript)
+		if err != nil {
+			return errors.Wrap(err, "reading auth script")
+		}
+		// fail early on syntax errors rather than after the browsers have started
+		if _, err := goja.Compile(s.cfg.AuthScript, string(src), false); err != nil {
+			return errors.Wrap(err, "compiling auth script")
+		}
+	case browserk.Form:
+		if s.cfg.Credentials == nil {
+			return ErrNoCredentials
+		}
+	case browserk.Header:
+		if s.needsToken() && s.cfg.TokenCommand == "" && s.cfg.TokenURL == "" {
+			return ErrNoTokenSource
+		}
+	case browserk.OAuth2:
+		return s.validateOAuth()
+	default:
+		return ErrUnsupportedAuthType
+	}
 	return nil
 }
 
-func (s *Service) Login(c *browserk.Context) {
+// Login with the provided browser depending on the configured AuthType, capturing
+// the session on success
+func (s *Service) Login(c *browserk.Context, browser browserk.Browser) error {
+	var err error
+
+	switch s.cfg.AuthType {
+	case browserk.Script:
+		err = s.scriptLogin(c, browser)
+	case browserk.Form:
+		err = s.formLogin(c, browser)
+	case browserk.Header:
+		err = s.headerLogin(c, browser)
+	case browserk.OAuth2:
+		err = s.oauthLogin(c, browser)
+	default:
+		err = ErrUnsupportedAuthType
+	}
 
+	if err != nil {
+		return err
+	}
+	return s.captureSession(browser)
 }
 
+// MustLogin returns true if authentication was configured
 func (s *Service) MustLogin() bool {
+	switch s.cfg.AuthType {
+	case browserk.Script:
+		return s.cfg.AuthScript != ""
+	case browserk.Form:
+		return s.cfg.Credentials != nil
+	case browserk.Header:
+		return len(s.cfg.AuthHeaders) > 0
+	case browserk.OAuth2:
+		return s.cfg.OAuth != nil
+	}
 	return false
 }
+
+// Session returns the session captured after the last successful login
+func (s *Service) Session() *browserk.Session {
+	s.sessionMutex.RLock()
+	defer s.sessionMutex.RUnlock()
+	return s.session
+}
+
+// RestoreSession applies the captured session to a (new) browser
+func (s *Service) RestoreSession(c *browserk.Context, browser browserk.Browser) error {
+	session := s.Session()
+	if session == nil {
+		return nil
+	}
+	return browser.SetCookies(session.Cookies)
+}
+
+func (s *Service) captureSession(browser browserk.Browser) error {
+	cookies, err := browser.GetCookies()
+	if err != nil {
+		return errors.Wrap(err, "capturing session cookies")
+	}
+	currentURL, _ := browser.GetURL()
+
+	s.sessionMutex.Lock()
+	s.session = &browserk.Session{
+		Cookies:  cookies,
+		URL:      currentURL,
+		Observed: time.Now(),
+	}
+	s.sessionMutex.Unlock()
+	return nil
+}
+
+func (s *Service) validateOAuth() error {
+	oauth := s.cfg.OAuth
+	if oauth.TokenURL == "" || oauth.ClientID == "" {
+		return errors.New("oauth2 requires a token url and client id")
+	}
+
+	switch oauth.Grant {
+	case browserk.GrantClientCredentials:
+	case browserk.GrantPassword:
+		if s.cfg.Credentials == nil {
+			return ErrNoCredentials
+		}
+	case browserk.GrantAuthorizationCode:
+		if oauth.AuthorizeURL == "" || oauth.RedirectURL == "" {
+			return errors.New("oauth2 authorization_code requires an authorize and redirect url")
+		}
+	default:
+		return ErrUnsupportedGrant
+	}
+	return nil
+}
+
+func (s *Service) loginURL() string {
+	if s.cfg.LoginURL != "" {
+		return s.cfg.LoginURL
+	}
+	return s.cfg.URL
+}
+
+// hasLoginPage returns true if landing on the login url means the session was lost. Header and
+// OAuth2 auth never visit a login page so the target url is not one unless it was set explicitly
+func (s *Service) hasLoginPage() bool {
+	if s.cfg.LoginURL != "" {
+		return true
+	}
+	return s.cfg.AuthType == browserk.Form || s.cfg.AuthType == browserk.Script
+}
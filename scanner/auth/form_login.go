@@ -0,0 +1,269 @@
+package auth
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"gitlab.com/browserker/browserk"
+	"gitlab.com/browserker/scanner/crawler"
+)
+
+// UserFieldRe matches the name/id of inputs that usually take the username or email
+var UserFieldRe = regexp.MustCompile("(?i)user|login|e.?mail|account|ident|uid")
+
+// formLogin navigates to the login url, finds the form with a password field,
+// fills it with our credentials and submits it.
+func (s *Service) formLogin(c *browserk.Context, browser browserk.Browser) error {
+	loginURL := s.loginURL()
+	if _, _, err := browser.ExecuteAction(c.Ctx, browserk.NewLoadURLAction(loginURL)); err != nil {
+		return errors.Wrap(err, "loading login url")
+	}
+
+	browser.RefreshDocument()
+	forms, err := browser.FindForms()
+	if err != nil {
+		return errors.Wrap(err, "finding login form")
+	}
+
+	form := FindLoginForm(forms)
+	if form == nil {
+		return ErrLoginFormNotFound
+	}
+
+	if !FillLoginForm(form, s.cfg.Credentials) {
+		return ErrLoginFormNotFound
+	}
+
+	c.Log.Info().Str("url", loginURL).Msg("submitting login form")
+	act := &browserk.Action{Type: browserk.ActFillForm, Form: form}
+	if _, _, err := browser.ExecuteAction(c.Ctx, act); err != nil {
+		return errors.Wrap(err, "submitting login form")
+	}
+
+	if !s.waitLoggedIn(c, browser, loginURL) {
+		return ErrLoginFailed
+	}
+	c.Log.Info().Msg("login succeeded")
+	return nil
+}
+
+// waitLoggedIn polls the browser until the logged in indicator shows up or we time out
+func (s *Service) waitLoggedIn(c *browserk.Context, browser browserk.Browser, loginURL string) bool {
+	ticker := time.NewTicker(250 * time.Millisecond)
+	defer ticker.Stop()
+	timeout := time.After(s.loginTimeout)
+
+	otpSubmitted := false
+	for {
+		// the code form usually shows up after the password was accepted
+		if !otpSubmitted && s.cfg.Credentials != nil && s.cfg.Credentials.TOTPSecret != "" {
+			otpSubmitted = s.submitOneTimeCode(c, browser)
+		}
+
+		if s.IsLoggedIn(browser, loginURL) {
+			return true
+		}
+
+		select {
+		case <-ticker.C:
+		case <-timeout:
+			return false
+		case <-c.Ctx.Done():
+			return false
+		}
+	}
+}
+
+// IsLoggedIn checks the configured indicator, if neither an element nor a cookie
+// was configured, we consider a url change with no password field remaining a success
+func (s *Service) IsLoggedIn(browser browserk.Browser, loginURL string) bool {
+	if s.cfg.LoggedInElement == "" && s.cfg.LoggedInCookie == "" {
+		currentURL, err := browser.GetURL()
+		if err != nil || currentURL == loginURL {
+			return false
+		}
+		browser.RefreshDocument()
+		inputs, _ := browser.FindElements("input[type=password]")
+		return len(inputs) == 0
+	}
+
+	if s.cfg.LoggedInElement != "" {
+		browser.RefreshDocument()
+		if eles, err := browser.FindElements(s.cfg.LoggedInElement); err != nil || len(eles) == 0 {
+			return false
+		}
+	}
+
+	if s.cfg.LoggedInCookie != "" {
+		cookies, err := browser.GetCookies()
+		if err != nil || !hasCookie(cookies, s.cfg.LoggedInCookie) {
+			return false
+		}
+	}
+	return true
+}
+
+// submitOneTimeCode fills any one time code input with a fresh TOTP code and submits its form
+func (s *Service) submitOneTimeCode(c *browserk.Context, browser browserk.Browser) bool {
+	browser.RefreshDocument()
+	forms, err := browser.FindForms()
+	if err != nil {
+		return false
+	}
+
+	form := FindOneTimeCodeForm(forms)
+	if form == nil {
+		return false
+	}
+
+	code, err := s.cfg.Credentials.TOTP(time.Now())
+	if err != nil {
+		c.Log.Error().Err(err).Msg("failed to generate one time code")
+		return false
+	}
+
+	if !FillOneTimeCodeForm(form, code) {
+		return false
+	}
+
+	c.Log.Info().Msg("submitting one time code")
+	act := &browserk.Action{Type: browserk.ActFillForm, Form: form}
+	if _, _, err := browser.ExecuteAction(c.Ctx, act); err != nil {
+		c.Log.Warn().Err(err).Msg("failed to submit one time code form")
+		return false
+	}
+	return true
+}
+
+// FindOneTimeCodeForm returns the first form that contains a one time code input
+func FindOneTimeCodeForm(forms []*browserk.HTMLFormElement) *browserk.HTMLFormElement {
+	for _, form := range forms {
+		for _, child := range form.ChildElements {
+			if crawler.IsOneTimeCodeElement(child) {
+				return form
+			}
+		}
+	}
+	return nil
+}
+
+// FillOneTimeCodeForm sets the code on every one time code input and picks the submit button
+func FillOneTimeCodeForm(form *browserk.HTMLFormElement, code string) bool {
+	filled := false
+	for _, child := range form.ChildElements {
+		if crawler.IsOneTimeCodeElement(child) {
+			child.Value = code
+			filled = true
+		}
+	}
+	form.SubmitButtonID = findSubmit(form)
+	return filled && form.SubmitButtonID != nil
+}
+
+// FindLoginForm returns the first form that contains a password input
+func FindLoginForm(forms []*browserk.HTMLFormElement) *browserk.HTMLFormElement {
+	for _, form := range forms {
+		for _, child := range form.ChildElements {
+			if child.Type == browserk.INPUT && strings.ToLower(child.GetAttribute("type")) == "password" {
+				return form
+			}
+		}
+	}
+	return nil
+}
+
+// FillLoginForm sets the credentials on the user and password inputs and picks the submit
+// button. Returns false if we could not find a password field or way to submit.
+func FillLoginForm(form *browserk.HTMLFormElement, creds *browserk.Credentials) bool {
+	var userField, passField *browserk.HTMLElement
+	candidates := make([]*browserk.HTMLElement, 0)
+
+	for _, child := range form.ChildElements {
+		if child.Type != browserk.INPUT {
+			continue
+		}
+
+		// some sites ask for the code on the same form as the password
+		if passField != nil && crawler.IsOneTimeCodeElement(child) {
+			if code, err := creds.TOTP(time.Now()); err == nil {
+				child.Value = code
+			}
+			continue
+		}
+
+		switch strings.ToLower(child.GetAttribute("type")) {
+		case "password":
+			if passField == nil {
+				passField = child
+			}
+		case "", "text", "email", "tel":
+			// we only care about the identity field that comes before the password
+			if passField == nil {
+				candidates = append(candidates, child)
+			}
+		}
+	}
+	form.SubmitButtonID = findSubmit(form)
+
+	if passField == nil || form.SubmitButtonID == nil {
+		return false
+	}
+
+	for _, candidate := range candidates {
+		if UserFieldRe.MatchString(candidate.GetAttribute("name")) || UserFieldRe.MatchString(candidate.GetAttribute("id")) {
+			userField = candidate
+			break
+		}
+	}
+
+	if userField == nil && len(candidates) > 0 {
+		userField = candidates[len(candidates)-1]
+	}
+
+	if userField != nil {
+		userField.Value = identity(userField, creds)
+	}
+	passField.Value = creds.Password
+	return true
+}
+
+// identity returns the email for email type fields if we have it, otherwise the username
+func identity(field *browserk.HTMLElement, creds *browserk.Credentials) string {
+	isEmail := strings.ToLower(field.GetAttribute("type")) == "email"
+	if (isEmail || creds.Username == "") && creds.Email != "" {
+		return creds.Email
+	}
+	return creds.Username
+}
+
+// findSubmit returns the hash of the button that submits the form, buttons take precedence
+// over submit inputs
+func findSubmit(form *browserk.HTMLFormElement) []byte {
+	var submit []byte
+	for _, child := range form.ChildElements {
+		inputType := strings.ToLower(child.GetAttribute("type"))
+		switch child.Type {
+		case browserk.INPUT:
+			if (inputType == "submit" || inputType == "image") && submit == nil {
+				submit = child.Hash()
+			}
+		case browserk.BUTTON:
+			// buttons inside of forms default to submit
+			if inputType == "" || inputType == "submit" {
+				submit = child.Hash()
+			}
+		}
+	}
+	return submit
+}
+
+func hasCookie(cookies []*browserk.Cookie, name string) bool {
+	for _, cookie := range cookies {
+		if cookie.Name == name {
+			return true
+		}
+	}
+	return false
+}
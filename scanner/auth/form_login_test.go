@@ -0,0 +1,74 @@
+package auth_test
+
+import (
+	"bytes"
+	"testing"
+
+	"gitlab.com/browserker/browserk"
+	"gitlab.com/browserker/mock"
+	"gitlab.com/browserker/scanner/auth"
+)
+
+func TestFillLoginForm(t *testing.T) {
+	creds := &browserk.Credentials{
+		Username: "admin",
+		Password: "hunter2",
+	}
+
+	forms := []*browserk.HTMLFormElement{mock.MakeMockAddressForm(), mock.MakeMockLoginForm()}
+	form := auth.FindLoginForm(forms)
+	if form == nil || form.FormType != browserk.FormLogin {
+		t.Fatalf("expected to find the login form")
+	}
+
+	if !auth.FillLoginForm(form, creds) {
+		t.Fatalf("expected login form to be filled")
+	}
+
+	var expected = map[string]string{
+		"csrf":   "",
+		"login":  "admin",
+		"pass":   "hunter2",
+		"search": "",
+	}
+	for _, ele := range form.ChildElements {
+		if ele.Type != browserk.INPUT {
+			continue
+		}
+		name := ele.GetAttribute("name")
+		if ele.Value != expected[name] {
+			t.Fatalf("expected %s to be [%s] got [%s]\n", name, expected[name], ele.Value)
+		}
+	}
+
+	submit := form.ChildElements[len(form.ChildElements)-1]
+	if !bytes.Equal(form.SubmitButtonID, submit.Hash()) {
+		t.Fatalf("expected submit button to be selected")
+	}
+
+	if auth.FindLoginForm([]*browserk.HTMLFormElement{mock.MakeMockAddressForm()}) != nil {
+		t.Fatalf("address form should not be a login form")
+	}
+}
+
+func TestFillOneTimeCodeForm(t *testing.T) {
+	forms := []*browserk.HTMLFormElement{mock.MakeMockLoginForm(), mock.MakeMockOneTimeCodeForm()}
+	form := auth.FindOneTimeCodeForm(forms)
+	if form == nil || form.GetAttribute("action") != "/mfa" {
+		t.Fatalf("expected to find the one time code form")
+	}
+
+	if !auth.FillOneTimeCodeForm(form, "123456") {
+		t.Fatalf("expected one time code form to be filled")
+	}
+
+	for _, child := range form.ChildElements {
+		name := child.GetAttribute("name")
+		if name == "mfa_code" && child.Value != "123456" {
+			t.Fatalf("expected code to be set got %s\n", child.Value)
+		}
+		if name == "postal_code" && child.Value != "" {
+			t.Fatalf("expected postal code to be left alone got %s\n", child.Value)
+		}
+	}
+}
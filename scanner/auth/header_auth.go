@@ -0,0 +1,221 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/wirepair/gcd/gcdapi"
+	"gitlab.com/browserker/browserk"
+)
+
+// TokenPlaceholder in AuthHeaders values is replaced with the current token
+const TokenPlaceholder = "{token}"
+
+// tokenSkew refreshes tokens a bit before they actually expire
+const tokenSkew = time.Second * 30
+
+// tokenResponse is the json a token endpoint may respond with
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	Token        string `json:"token"`
+	ExpiresIn    int    `json:"expires_in"`
+	Error        string `json:"error"`
+}
+
+// AddHandlers registers the request handler for header and token based auth on the context
+func (s *Service) AddHandlers(c *browserk.Context) {
+	switch s.cfg.AuthType {
+	case browserk.Header, browserk.OAuth2:
+		if s.MustLogin() {
+			c.AddReqHandler(s.AddAuthHeaders)
+		}
+	}
+}
+
+// AddAuthHeaders is a browserk.RequestHandler that adds the configured headers to in scope requests
+func (s *Service) AddAuthHeaders(c *browserk.Context, browser browserk.Browser, i *browserk.InterceptedHTTPRequest) {
+	if i.Request == nil || (c.Scope != nil && c.Scope.Check(i.Request.Url) != browserk.InScope) {
+		return
+	}
+
+	token, err := s.Token(c.Ctx)
+	if err != nil {
+		c.Log.Error().Err(err).Msg("failed to get auth token")
+		return
+	}
+
+	// modified headers override all of the request headers, so start from what we were sent.
+	// the entries are copied so the captured request headers keep their original values
+	headers := i.Modified.Headers
+	if headers == nil {
+		headers = i.RequestHeaders
+	}
+	headers = copyHeaders(headers)
+	for name, value := range s.authHeaders() {
+		headers = setHeader(headers, name, strings.Replace(value, TokenPlaceholder, token, -1))
+	}
+	i.Modified.Headers = headers
+}
+
+// Token returns the current token, refreshing it if it expired
+func (s *Service) Token(ctx context.Context) (string, error) {
+	s.tokenMutex.Lock()
+	defer s.tokenMutex.Unlock()
+
+	if s.token != "" && (s.tokenExpires.IsZero() || time.Now().Add(tokenSkew).Before(s.tokenExpires)) {
+		return s.token, nil
+	}
+	return s.refreshToken(ctx)
+}
+
+// headerLogin forces a token refresh, called on the initial login and whenever
+// the session was detected as lost
+func (s *Service) headerLogin(c *browserk.Context, browser browserk.Browser) error {
+	if !s.needsToken() {
+		return nil
+	}
+
+	s.tokenMutex.Lock()
+	defer s.tokenMutex.Unlock()
+	_, err := s.refreshToken(c.Ctx)
+	return err
+}
+
+// refreshToken from the command or the token endpoint, tokenMutex must be held
+func (s *Service) refreshToken(ctx context.Context) (string, error) {
+	var token string
+	var expiresIn int
+	var err error
+
+	switch {
+	case !s.needsToken():
+		return "", nil
+	case s.cfg.AuthType == browserk.OAuth2:
+		token, expiresIn, err = s.oauthRefresh(ctx)
+	case s.cfg.TokenCommand != "":
+		token, err = s.commandToken(ctx)
+	case s.cfg.TokenURL != "":
+		token, expiresIn, err = s.endpointToken(ctx)
+	default:
+		err = ErrNoTokenSource
+	}
+
+	if err != nil {
+		return "", err
+	}
+	if token == "" {
+		return "", ErrEmptyToken
+	}
+
+	if expiresIn == 0 {
+		expiresIn = s.cfg.TokenExpiry
+	}
+	s.tokenExpires = time.Time{}
+	if expiresIn > 0 {
+		s.tokenExpires = time.Now().Add(time.Duration(expiresIn) * time.Second)
+	}
+	s.token = token
+	return token, nil
+}
+
+func (s *Service) commandToken(ctx context.Context) (string, error) {
+	out, err := exec.CommandContext(ctx, "sh", "-c", s.cfg.TokenCommand).Output()
+	if err != nil {
+		return "", errors.Wrap(err, "running token command")
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// endpointToken posts the credentials (if any) to the token url
+func (s *Service) endpointToken(ctx context.Context) (string, int, error) {
+	form := url.Values{}
+	if s.cfg.Credentials != nil {
+		form.Set("username", identityOrUser(s.cfg.Credentials))
+		form.Set("password", s.cfg.Credentials.Password)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.cfg.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", 0, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return "", 0, errors.Wrap(err, "requesting token")
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", 0, errors.Wrap(err, "reading token response")
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", 0, errors.Errorf("token endpoint returned status %d", resp.StatusCode)
+	}
+
+	tokenResp := &tokenResponse{}
+	if err := json.Unmarshal(body, tokenResp); err != nil {
+		// not json, treat the body as the token
+		return strings.TrimSpace(string(body)), 0, nil
+	}
+
+	if tokenResp.AccessToken != "" {
+		return tokenResp.AccessToken, tokenResp.ExpiresIn, nil
+	}
+	return tokenResp.Token, tokenResp.ExpiresIn, nil
+}
+
+// authHeaders to add, OAuth2 defaults to a bearer token
+func (s *Service) authHeaders() map[string]string {
+	if s.cfg.AuthType == browserk.OAuth2 && len(s.cfg.AuthHeaders) == 0 {
+		return map[string]string{"Authorization": "Bearer " + TokenPlaceholder}
+	}
+	return s.cfg.AuthHeaders
+}
+
+// needsToken returns true if any of the configured headers use the token
+func (s *Service) needsToken() bool {
+	for _, value := range s.authHeaders() {
+		if strings.Contains(value, TokenPlaceholder) {
+			return true
+		}
+	}
+	return false
+}
+
+func identityOrUser(creds *browserk.Credentials) string {
+	if creds.Username == "" {
+		return creds.Email
+	}
+	return creds.Username
+}
+
+// copyHeaders into a new slice of new entries
+func copyHeaders(headers []*gcdapi.FetchHeaderEntry) []*gcdapi.FetchHeaderEntry {
+	copied := make([]*gcdapi.FetchHeaderEntry, 0, len(headers)+1)
+	for _, header := range headers {
+		copied = append(copied, &gcdapi.FetchHeaderEntry{Name: header.Name, Value: header.Value})
+	}
+	return copied
+}
+
+// setHeader replaces the value of an existing header (case insensitive) or adds it
+func setHeader(headers []*gcdapi.FetchHeaderEntry, name, value string) []*gcdapi.FetchHeaderEntry {
+	for _, header := range headers {
+		if strings.EqualFold(header.Name, name) {
+			header.Value = value
+			return headers
+		}
+	}
+	return append(headers, &gcdapi.FetchHeaderEntry{Name: name, Value: value})
+}
@@ -0,0 +1,97 @@
+package auth_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rs/zerolog/log"
+	"github.com/wirepair/gcd/gcdapi"
+	"gitlab.com/browserker/browserk"
+	"gitlab.com/browserker/mock"
+	"gitlab.com/browserker/scanner/auth"
+)
+
+func TestHeaderAuth(t *testing.T) {
+	issued := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("username") != "admin" || r.FormValue("password") != "hunter2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		issued++
+		fmt.Fprintf(w, `{"access_token": "token%d", "expires_in": 3600}`, issued)
+	}))
+	defer srv.Close()
+
+	cfg := &browserk.Config{
+		AuthType:    browserk.Header,
+		AuthHeaders: map[string]string{"Authorization": "Bearer {token}", "X-Api-Key": "static"},
+		TokenURL:    srv.URL,
+		Credentials: &browserk.Credentials{Username: "admin", Password: "hunter2"},
+	}
+	s := auth.New(cfg)
+	if err := s.Init(); err != nil {
+		t.Fatalf("error init auth service: %s\n", err)
+	}
+
+	ctx := &browserk.Context{Ctx: context.Background(), Log: &log.Logger}
+	s.AddHandlers(ctx)
+
+	req := &browserk.InterceptedHTTPRequest{
+		Request:        &gcdapi.NetworkRequest{Url: "http://example.com/api"},
+		RequestHeaders: []*gcdapi.FetchHeaderEntry{{Name: "authorization", Value: "old"}, {Name: "Accept", Value: "*/*"}},
+		Modified:       &browserk.HTTPModifiedRequest{},
+	}
+	ctx.Copy().NextReq(nil, req)
+
+	expected := map[string]string{"authorization": "Bearer token1", "Accept": "*/*", "X-Api-Key": "static"}
+	if len(req.Modified.Headers) != len(expected) {
+		t.Fatalf("expected %d headers got %d\n", len(expected), len(req.Modified.Headers))
+	}
+	for _, header := range req.Modified.Headers {
+		if expected[header.Name] != header.Value {
+			t.Fatalf("expected %s to be %s got %s\n", header.Name, expected[header.Name], header.Value)
+		}
+	}
+
+	// the captured request keeps the headers it was sent with
+	if len(req.RequestHeaders) != 2 || req.RequestHeaders[0].Value != "old" {
+		t.Fatalf("expected original request headers to be unchanged got %s=%s\n", req.RequestHeaders[0].Name, req.RequestHeaders[0].Value)
+	}
+
+	// token is cached until it expires or we login again
+	if token, _ := s.Token(ctx.Ctx); token != "token1" {
+		t.Fatalf("expected cached token got %s\n", token)
+	}
+
+	if err := s.Login(ctx, mock.MakeMockBrowser()); err != nil {
+		t.Fatalf("error logging in: %s\n", err)
+	}
+	if token, _ := s.Token(ctx.Ctx); token != "token2" {
+		t.Fatalf("expected refreshed token got %s\n", token)
+	}
+}
+
+func TestHeaderAuthCommand(t *testing.T) {
+	cfg := &browserk.Config{
+		AuthType:     browserk.Header,
+		AuthHeaders:  map[string]string{"Authorization": "Bearer {token}"},
+		TokenCommand: "echo cmdtoken",
+	}
+	s := auth.New(cfg)
+	if err := s.Init(); err != nil {
+		t.Fatalf("error init auth service: %s\n", err)
+	}
+
+	if token, err := s.Token(context.Background()); err != nil || token != "cmdtoken" {
+		t.Fatalf("expected token from command got %s %v\n", token, err)
+	}
+
+	cfg.TokenCommand = ""
+	if err := auth.New(cfg).Init(); err != auth.ErrNoTokenSource {
+		t.Fatalf("expected no token source error got %v\n", err)
+	}
+}
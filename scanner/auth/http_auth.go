@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"net/url"
+	"strings"
+
+	"gitlab.com/browserker/browserk"
+)
+
+// HTTPCredentials returns the username and password to answer a basic/digest/ntlm challenge with.
+// Hosts configured in HTTPCredentials get their own credentials, any other in scope host gets the
+// configured Credentials and out of scope hosts get nothing. NoAuth never answers.
+func (s *Service) HTTPCredentials(c *browserk.Context, challenge *browserk.AuthChallengeEvent) (string, string, bool) {
+	if s.cfg.AuthType == browserk.NoAuth {
+		return "", "", false
+	}
+
+	target := challenge.Origin
+	if target == "" {
+		target = challenge.URL
+	}
+
+	u, err := url.Parse(target)
+	if err != nil || u.Host == "" {
+		return "", "", false
+	}
+
+	creds, ok := s.cfg.HTTPCredentials[u.Host]
+	if !ok {
+		creds, ok = s.cfg.HTTPCredentials[u.Hostname()]
+	}
+
+	if !ok {
+		if c.Scope == nil || c.Scope.Check(challenge.URL) != browserk.InScope {
+			return "", "", false
+		}
+		creds = s.cfg.Credentials
+	}
+
+	if creds == nil {
+		return "", "", false
+	}
+
+	username := identityOrUser(creds)
+	if creds.Domain != "" && strings.EqualFold(challenge.Scheme, "ntlm") {
+		username = creds.Domain + `\` + username
+	}
+	return username, creds.Password, true
+}
@@ -0,0 +1,52 @@
+package auth_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/rs/zerolog/log"
+	"gitlab.com/browserker/browserk"
+	"gitlab.com/browserker/scanner"
+	"gitlab.com/browserker/scanner/auth"
+)
+
+func TestAuthChallenge(t *testing.T) {
+	target, _ := url.Parse("http://intranet.example.com/")
+	cfg := &browserk.Config{
+		URL:         target.String(),
+		AuthType:    browserk.Form,
+		Credentials: &browserk.Credentials{Username: "admin", Password: "hunter2", Domain: "CORP"},
+		HTTPCredentials: map[string]*browserk.Credentials{
+			"proxy.example.com:8080": {Username: "proxyuser", Password: "proxypass"},
+		},
+	}
+	s := auth.New(cfg)
+	ctx := &browserk.Context{Ctx: context.Background(), Log: &log.Logger, Scope: scanner.NewScopeService(target)}
+
+	var tests = []struct {
+		name      string
+		challenge *browserk.AuthChallengeEvent
+		username  string
+		password  string
+		ok        bool
+	}{
+		{"in scope basic", &browserk.AuthChallengeEvent{URL: "http://intranet.example.com/admin", Origin: "http://intranet.example.com", Scheme: "basic"}, "admin", "hunter2", true},
+		{"in scope ntlm", &browserk.AuthChallengeEvent{URL: "http://intranet.example.com/admin", Origin: "http://intranet.example.com", Scheme: "NTLM"}, `CORP\admin`, "hunter2", true},
+		{"host credentials", &browserk.AuthChallengeEvent{URL: "http://other.com/", Origin: "http://proxy.example.com:8080", Scheme: "digest", Source: "Proxy"}, "proxyuser", "proxypass", true},
+		{"out of scope", &browserk.AuthChallengeEvent{URL: "http://evil.com/", Origin: "http://evil.com", Scheme: "basic"}, "", "", false},
+	}
+
+	for _, tt := range tests {
+		username, password, ok := s.HTTPCredentials(ctx, tt.challenge)
+		if ok != tt.ok || username != tt.username || password != tt.password {
+			t.Fatalf("%s: expected %s/%s/%v got %s/%s/%v\n", tt.name, tt.username, tt.password, tt.ok, username, password, ok)
+		}
+	}
+
+	// the unauthenticated identity never answers challenges
+	cfg.AuthType = browserk.NoAuth
+	if _, _, ok := auth.New(cfg).HTTPCredentials(ctx, tests[0].challenge); ok {
+		t.Fatalf("expected no credentials without auth\n")
+	}
+}
@@ -0,0 +1,111 @@
+package auth
+
+import (
+	"net/http"
+	"net/url"
+
+	"gitlab.com/browserker/browserk"
+)
+
+// IsLoggedOut checks the result of a navigation for signs the session was lost: being redirected
+// to the login page (if there is one), a 401/403 on the top level document, the session cookie disappearing
+// or the body matching the configured logged out regex.
+func (s *Service) IsLoggedOut(c *browserk.Context, browser browserk.Browser, nav *browserk.Navigation, result *browserk.NavigationResult) bool {
+	if !s.MustLogin() || result == nil {
+		return false
+	}
+	// only check for the login url if there is a login page to end up on
+	loginURL := ""
+	if s.hasLoginPage() {
+		loginURL = s.loginURL()
+	}
+
+	// navigating to the login page ourselves is not a sign of being logged out
+	if nav != nil && nav.Action != nil && nav.Action.Type == browserk.ActLoadURL && sameURL(string(nav.Action.Input), loginURL) {
+		return false
+	}
+
+	if !sameURL(result.StartURL, loginURL) && sameURL(result.EndURL, loginURL) {
+		c.Log.Info().Str("url", result.EndURL).Msg("logged out: ended up on login url")
+		return true
+	}
+
+	for _, msg := range result.Messages {
+		if msg.Request == nil || msg.Request.Type != "Document" {
+			continue
+		}
+
+		if msg.Request.RedirectResponse != nil && sameURL(msg.Request.Request.Url, loginURL) {
+			c.Log.Info().Str("url", msg.Request.RedirectResponse.Url).Msg("logged out: redirected to login url")
+			return true
+		}
+
+		if msg.Response == nil || msg.Response.Response == nil {
+			continue
+		}
+
+		// frames and other documents may be denied while we are still logged in
+		if !sameDocument(msg.Response.Response.Url, result.EndURL) {
+			continue
+		}
+
+		status := msg.Response.Response.Status
+		if status == http.StatusUnauthorized || status == http.StatusForbidden {
+			c.Log.Info().Str("url", msg.Response.Response.Url).Int("status", status).Msg("logged out: document request was denied")
+			return true
+		}
+	}
+
+	if s.cfg.LoggedInCookie != "" {
+		cookies, err := browser.GetCookies()
+		if err == nil && !hasCookie(cookies, s.cfg.LoggedInCookie) {
+			c.Log.Info().Str("cookie", s.cfg.LoggedInCookie).Msg("logged out: session cookie missing")
+			return true
+		}
+	}
+
+	if s.loggedOutRe != nil && s.loggedOutRe.MatchString(result.DOM) {
+		c.Log.Info().Str("url", result.EndURL).Msg("logged out: body matched logged out regex")
+		return true
+	}
+	return false
+}
+
+// sameURL compares the scheme, host and path of two urls ignoring query and fragments
+func sameURL(a, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+	ua, err := url.Parse(a)
+	if err != nil {
+		return false
+	}
+	ub, err := url.Parse(b)
+	if err != nil {
+		return false
+	}
+	return ua.Scheme == ub.Scheme && ua.Host == ub.Host && normalizePath(ua.Path) == normalizePath(ub.Path)
+}
+
+// sameDocument compares two urls ignoring the fragment
+func sameDocument(a, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+	ua, err := url.Parse(a)
+	if err != nil {
+		return false
+	}
+	ub, err := url.Parse(b)
+	if err != nil {
+		return false
+	}
+	return sameURL(a, b) && ua.RawQuery == ub.RawQuery
+}
+
+func normalizePath(path string) string {
+	if path == "" {
+		return "/"
+	}
+	return path
+}
@@ -0,0 +1,103 @@
+package auth_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rs/zerolog/log"
+	"github.com/wirepair/gcd/gcdapi"
+	"gitlab.com/browserker/browserk"
+	"gitlab.com/browserker/mock"
+	"gitlab.com/browserker/scanner/auth"
+)
+
+func TestIsLoggedOut(t *testing.T) {
+	cfg := &browserk.Config{
+		URL:            "http://example.com/",
+		LoginURL:       "http://example.com/login",
+		AuthType:       browserk.Form,
+		Credentials:    &browserk.Credentials{Username: "admin", Password: "hunter2"},
+		LoggedInCookie: "session",
+		LoggedOutRegex: "(?i)your session has expired",
+	}
+	s := auth.New(cfg)
+	if err := s.Init(); err != nil {
+		t.Fatalf("error init auth service: %s\n", err)
+	}
+
+	b := mock.MakeMockBrowser()
+	b.GetCookiesFn = func() ([]*browserk.Cookie, error) {
+		return []*browserk.Cookie{{Name: "session", Value: "1234"}}, nil
+	}
+	ctx := &browserk.Context{Ctx: context.Background(), Log: &log.Logger}
+	nav := browserk.NewNavigation(browserk.TrigCrawler, &browserk.Action{Type: browserk.ActLeftClick})
+
+	document := func(url string, status int) *browserk.HTTPMessage {
+		return &browserk.HTTPMessage{
+			Request:  &browserk.HTTPRequest{Type: "Document", Request: &gcdapi.NetworkRequest{Url: url}},
+			Response: &browserk.HTTPResponse{Type: "Document", Response: &gcdapi.NetworkResponse{Url: url, Status: status}},
+		}
+	}
+
+	var tests = []struct {
+		name     string
+		nav      *browserk.Navigation
+		result   *browserk.NavigationResult
+		expected bool
+	}{
+		{"logged in", nav, &browserk.NavigationResult{StartURL: "http://example.com/", EndURL: "http://example.com/profile", Messages: []*browserk.HTTPMessage{document("http://example.com/profile", 200)}}, false},
+		{"ended on login", nav, &browserk.NavigationResult{StartURL: "http://example.com/", EndURL: "http://example.com/login?next=/profile"}, true},
+		{"landed on root", nav, &browserk.NavigationResult{StartURL: "http://example.com/profile", EndURL: "http://example.com/", Messages: []*browserk.HTTPMessage{document("http://example.com/", 200)}}, false},
+		{"loading login", browserk.NewNavigation(browserk.TrigCrawler, browserk.NewLoadURLAction("http://example.com/login")), &browserk.NavigationResult{StartURL: "about:blank", EndURL: "http://example.com/login"}, false},
+		{"document denied", nav, &browserk.NavigationResult{EndURL: "http://example.com/admin", Messages: []*browserk.HTTPMessage{document("http://example.com/admin", 401)}}, true},
+		{"frame denied", nav, &browserk.NavigationResult{EndURL: "http://example.com/profile#top", Messages: []*browserk.HTTPMessage{document("http://example.com/profile", 200), document("http://example.com/widgets/billing", 403)}}, false},
+		{"other document denied", nav, &browserk.NavigationResult{EndURL: "http://example.com/report?id=1", Messages: []*browserk.HTTPMessage{document("http://example.com/report?id=2", 403), document("http://example.com/report?id=1", 200)}}, false},
+		{"body matched", nav, &browserk.NavigationResult{EndURL: "http://example.com/profile", DOM: "<p>Your session has expired</p>"}, true},
+	}
+
+	for _, tt := range tests {
+		if got := s.IsLoggedOut(ctx, b, tt.nav, tt.result); got != tt.expected {
+			t.Fatalf("%s: expected %v got %v\n", tt.name, tt.expected, got)
+		}
+	}
+
+	b.GetCookiesFn = func() ([]*browserk.Cookie, error) {
+		return []*browserk.Cookie{}, nil
+	}
+	if !s.IsLoggedOut(ctx, b, nav, &browserk.NavigationResult{EndURL: "http://example.com/profile"}) {
+		t.Fatalf("expected missing session cookie to be logged out")
+	}
+}
+
+func TestIsLoggedOutNoLoginPage(t *testing.T) {
+	cfg := &browserk.Config{
+		URL:         "http://example.com/",
+		AuthType:    browserk.Header,
+		AuthHeaders: map[string]string{"Authorization": "Bearer 1234"},
+	}
+	s := auth.New(cfg)
+	if err := s.Init(); err != nil {
+		t.Fatalf("error init auth service: %s\n", err)
+	}
+
+	b := mock.MakeMockBrowser()
+	ctx := &browserk.Context{Ctx: context.Background(), Log: &log.Logger}
+	nav := browserk.NewNavigation(browserk.TrigCrawler, &browserk.Action{Type: browserk.ActLeftClick})
+
+	// the target url is not a login page for header auth
+	result := &browserk.NavigationResult{StartURL: "http://example.com/profile", EndURL: "http://example.com/"}
+	if s.IsLoggedOut(ctx, b, nav, result) {
+		t.Fatalf("landing on the target url should not be logged out without a login page")
+	}
+
+	result = &browserk.NavigationResult{
+		EndURL: "http://example.com/admin",
+		Messages: []*browserk.HTTPMessage{{
+			Request:  &browserk.HTTPRequest{Type: "Document", Request: &gcdapi.NetworkRequest{Url: "http://example.com/admin"}},
+			Response: &browserk.HTTPResponse{Type: "Document", Response: &gcdapi.NetworkResponse{Url: "http://example.com/admin", Status: 401}},
+		}},
+	}
+	if !s.IsLoggedOut(ctx, b, nav, result) {
+		t.Fatalf("expected a denied document to be logged out")
+	}
+}
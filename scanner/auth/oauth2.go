@@ -0,0 +1,287 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"gitlab.com/browserker/browserk"
+)
+
+// oauthLogin acquires a new token using the configured grant, for authorization_code
+// the browser is driven through the provider's login and consent pages.
+func (s *Service) oauthLogin(c *browserk.Context, browser browserk.Browser) error {
+	form := url.Values{}
+	oauth := s.cfg.OAuth
+
+	switch oauth.Grant {
+	case browserk.GrantClientCredentials:
+		form.Set("grant_type", browserk.GrantClientCredentials)
+	case browserk.GrantPassword:
+		form.Set("grant_type", browserk.GrantPassword)
+		form.Set("username", identityOrUser(s.cfg.Credentials))
+		form.Set("password", s.cfg.Credentials.Password)
+	case browserk.GrantAuthorizationCode:
+		// do not hold the token lock while the browser is out, our own request handler needs it
+		code, verifier, err := s.authorize(c, browser)
+		if err != nil {
+			return err
+		}
+		form.Set("grant_type", browserk.GrantAuthorizationCode)
+		form.Set("code", code)
+		form.Set("redirect_uri", oauth.RedirectURL)
+		form.Set("code_verifier", verifier)
+	default:
+		return ErrUnsupportedGrant
+	}
+
+	if len(oauth.Scopes) > 0 && oauth.Grant != browserk.GrantAuthorizationCode {
+		form.Set("scope", strings.Join(oauth.Scopes, " "))
+	}
+
+	s.tokenMutex.Lock()
+	defer s.tokenMutex.Unlock()
+	resp, err := s.requestToken(c.Ctx, form)
+	if err != nil {
+		return err
+	}
+	s.setOAuthToken(resp)
+	c.Log.Info().Str("grant", oauth.Grant).Time("expires", s.tokenExpires).Msg("acquired oauth2 token")
+	return nil
+}
+
+// oauthRefresh is called with tokenMutex held when the token expired. Uses the refresh token
+// if we were given one, otherwise grants that don't need a browser simply request a new token.
+func (s *Service) oauthRefresh(ctx context.Context) (string, int, error) {
+	form := url.Values{}
+
+	switch {
+	case s.refresh != "":
+		form.Set("grant_type", "refresh_token")
+		form.Set("refresh_token", s.refresh)
+	case s.cfg.OAuth.Grant == browserk.GrantClientCredentials:
+		form.Set("grant_type", browserk.GrantClientCredentials)
+	case s.cfg.OAuth.Grant == browserk.GrantPassword:
+		form.Set("grant_type", browserk.GrantPassword)
+		form.Set("username", identityOrUser(s.cfg.Credentials))
+		form.Set("password", s.cfg.Credentials.Password)
+	default:
+		// authorization_code without a refresh token needs to login again
+		return "", 0, ErrTokenExpired
+	}
+
+	resp, err := s.requestToken(ctx, form)
+	if err != nil {
+		return "", 0, err
+	}
+
+	if resp.RefreshToken != "" {
+		s.refresh = resp.RefreshToken
+	}
+	return resp.AccessToken, resp.ExpiresIn, nil
+}
+
+// setOAuthToken stores the tokens from a token response, tokenMutex must be held
+func (s *Service) setOAuthToken(resp *tokenResponse) {
+	s.token = resp.AccessToken
+	s.refresh = resp.RefreshToken
+
+	expiresIn := resp.ExpiresIn
+	if expiresIn == 0 {
+		expiresIn = s.cfg.TokenExpiry
+	}
+	s.tokenExpires = time.Time{}
+	if expiresIn > 0 {
+		s.tokenExpires = time.Now().Add(time.Duration(expiresIn) * time.Second)
+	}
+}
+
+// requestToken posts the grant to the token endpoint, authenticating with the client secret if set
+func (s *Service) requestToken(ctx context.Context, form url.Values) (*tokenResponse, error) {
+	oauth := s.cfg.OAuth
+	form.Set("client_id", oauth.ClientID)
+
+	req, err := http.NewRequest(http.MethodPost, oauth.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if oauth.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(oauth.ClientID), url.QueryEscape(oauth.ClientSecret))
+	}
+
+	resp, err := s.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, errors.Wrap(err, "requesting oauth2 token")
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading oauth2 token response")
+	}
+
+	tokenResp := &tokenResponse{}
+	if err := json.Unmarshal(body, tokenResp); err != nil {
+		return nil, errors.Wrapf(err, "decoding oauth2 token response (status %d)", resp.StatusCode)
+	}
+
+	if tokenResp.Error != "" {
+		return nil, errors.Errorf("token endpoint returned error %s", tokenResp.Error)
+	}
+
+	if resp.StatusCode != http.StatusOK || tokenResp.AccessToken == "" {
+		return nil, errors.Errorf("token endpoint returned status %d without an access token", resp.StatusCode)
+	}
+	return tokenResp, nil
+}
+
+// authorize drives the browser to the authorization endpoint, logs in and grants
+// consent if asked, returning the code and PKCE verifier once we are redirected back.
+func (s *Service) authorize(c *browserk.Context, browser browserk.Browser) (string, string, error) {
+	oauth := s.cfg.OAuth
+	verifier := randomString()
+	state := randomString()
+	challenge := sha256.Sum256([]byte(verifier))
+
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", oauth.ClientID)
+	params.Set("redirect_uri", oauth.RedirectURL)
+	params.Set("state", state)
+	params.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	params.Set("code_challenge_method", "S256")
+	if len(oauth.Scopes) > 0 {
+		params.Set("scope", strings.Join(oauth.Scopes, " "))
+	}
+
+	authorizeURL := oauth.AuthorizeURL
+	if strings.Contains(authorizeURL, "?") {
+		authorizeURL += "&" + params.Encode()
+	} else {
+		authorizeURL += "?" + params.Encode()
+	}
+
+	c.Log.Info().Str("url", oauth.AuthorizeURL).Msg("starting oauth2 authorization")
+	if _, _, err := browser.ExecuteAction(c.Ctx, browserk.NewLoadURLAction(authorizeURL)); err != nil {
+		return "", "", errors.Wrap(err, "loading authorize url")
+	}
+
+	ticker := time.NewTicker(250 * time.Millisecond)
+	defer ticker.Stop()
+	timeout := time.After(s.loginTimeout)
+	submitted := false
+	consented := false
+
+	for {
+		if redirect := s.findRedirect(browser); redirect != nil {
+			query := redirect.Query()
+			if errCode := query.Get("error"); errCode != "" {
+				return "", "", errors.Errorf("authorization failed: %s %s", errCode, query.Get("error_description"))
+			}
+			if query.Get("state") != state {
+				return "", "", ErrStateMismatch
+			}
+			return query.Get("code"), verifier, nil
+		}
+
+		if !submitted && s.cfg.Credentials != nil {
+			submitted = s.submitProviderLogin(c, browser)
+		}
+
+		if !consented && oauth.ConsentSelector != "" {
+			consented = s.grantConsent(c, browser)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-timeout:
+			return "", "", ErrLoginFailed
+		case <-c.Ctx.Done():
+			return "", "", c.Ctx.Err()
+		}
+	}
+}
+
+// findRedirect looks at the current url and requests made for our redirect uri
+func (s *Service) findRedirect(browser browserk.Browser) *url.URL {
+	redirectURL := s.cfg.OAuth.RedirectURL
+	candidates := make([]string, 0)
+	if currentURL, err := browser.GetURL(); err == nil {
+		candidates = append(candidates, currentURL)
+	}
+
+	// the redirect uri may not even be served, so check what the browser requested
+	if messages, err := browser.GetMessages(); err == nil {
+		for _, msg := range messages {
+			if msg.Request != nil && msg.Request.Request != nil {
+				candidates = append(candidates, msg.Request.Request.Url)
+			}
+		}
+	}
+
+	for _, candidate := range candidates {
+		if !strings.HasPrefix(candidate, redirectURL) {
+			continue
+		}
+		if u, err := url.Parse(candidate); err == nil {
+			return u
+		}
+	}
+	return nil
+}
+
+// submitProviderLogin fills the provider's login form if one is shown
+func (s *Service) submitProviderLogin(c *browserk.Context, browser browserk.Browser) bool {
+	browser.RefreshDocument()
+	forms, err := browser.FindForms()
+	if err != nil {
+		return false
+	}
+
+	form := FindLoginForm(forms)
+	if form == nil || !FillLoginForm(form, s.cfg.Credentials) {
+		return false
+	}
+
+	c.Log.Info().Msg("submitting oauth2 provider login form")
+	act := &browserk.Action{Type: browserk.ActFillForm, Form: form}
+	if _, _, err := browser.ExecuteAction(c.Ctx, act); err != nil {
+		c.Log.Warn().Err(err).Msg("failed to submit provider login form")
+		return false
+	}
+	return true
+}
+
+// grantConsent clicks the consent button if the provider is asking
+func (s *Service) grantConsent(c *browserk.Context, browser browserk.Browser) bool {
+	browser.RefreshDocument()
+	eles, err := browser.FindElements(s.cfg.OAuth.ConsentSelector)
+	if err != nil || len(eles) == 0 {
+		return false
+	}
+
+	c.Log.Info().Msg("granting oauth2 consent")
+	act := &browserk.Action{Type: browserk.ActLeftClick, Element: eles[0]}
+	if _, _, err := browser.ExecuteAction(c.Ctx, act); err != nil {
+		c.Log.Warn().Err(err).Msg("failed to click consent")
+		return false
+	}
+	return true
+}
+
+// randomString for PKCE verifiers and state
+func randomString() string {
+	b := make([]byte, 32)
+	rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
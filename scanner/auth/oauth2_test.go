@@ -0,0 +1,187 @@
+package auth_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/rs/zerolog/log"
+	"gitlab.com/browserker/browserk"
+	"gitlab.com/browserker/mock"
+	"gitlab.com/browserker/scanner/auth"
+)
+
+// testIdP is a minimal stand-in OAuth2 provider
+type testIdP struct {
+	challenges map[string]string // code -> pkce challenge
+	issued     int
+}
+
+func (p *testIdP) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/authorize":
+		q := r.URL.Query()
+		if q.Get("code_challenge_method") != "S256" {
+			http.Error(w, "pkce required", http.StatusBadRequest)
+			return
+		}
+		code := fmt.Sprintf("code%d", len(p.challenges))
+		p.challenges[code] = q.Get("code_challenge")
+		http.Redirect(w, r, q.Get("redirect_uri")+"?code="+code+"&state="+url.QueryEscape(q.Get("state")), http.StatusFound)
+	case "/token":
+		if id, secret, ok := r.BasicAuth(); ok && (id != "client" || secret != "secret") {
+			p.fail(w, "invalid_client")
+			return
+		}
+
+		switch r.FormValue("grant_type") {
+		case "client_credentials":
+		case "password":
+			if r.FormValue("username") != "admin" || r.FormValue("password") != "hunter2" {
+				p.fail(w, "invalid_grant")
+				return
+			}
+		case "authorization_code":
+			sum := sha256.Sum256([]byte(r.FormValue("code_verifier")))
+			if p.challenges[r.FormValue("code")] != base64.RawURLEncoding.EncodeToString(sum[:]) {
+				p.fail(w, "invalid_grant")
+				return
+			}
+		case "refresh_token":
+			if r.FormValue("refresh_token") != fmt.Sprintf("refresh%d", p.issued) {
+				p.fail(w, "invalid_grant")
+				return
+			}
+		default:
+			p.fail(w, "unsupported_grant_type")
+			return
+		}
+		p.issued++
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token":  fmt.Sprintf("access%d", p.issued),
+			"refresh_token": fmt.Sprintf("refresh%d", p.issued),
+			"expires_in":    10, // within the refresh skew, so every Token call refreshes
+		})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (p *testIdP) fail(w http.ResponseWriter, reason string) {
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(map[string]string{"error": reason})
+}
+
+func TestOAuth2Grants(t *testing.T) {
+	srv := httptest.NewServer(&testIdP{challenges: make(map[string]string)})
+	defer srv.Close()
+
+	ctx := &browserk.Context{Ctx: context.Background(), Log: &log.Logger}
+
+	var tests = []struct {
+		grant string
+		creds *browserk.Credentials
+	}{
+		{browserk.GrantClientCredentials, nil},
+		{browserk.GrantPassword, &browserk.Credentials{Username: "admin", Password: "hunter2"}},
+		{browserk.GrantAuthorizationCode, nil},
+	}
+
+	for _, tt := range tests {
+		cfg := &browserk.Config{
+			AuthType:    browserk.OAuth2,
+			Credentials: tt.creds,
+			OAuth: &browserk.OAuthConfig{
+				Grant:        tt.grant,
+				AuthorizeURL: srv.URL + "/authorize",
+				TokenURL:     srv.URL + "/token",
+				RedirectURL:  "http://app.local/callback",
+				ClientID:     "client",
+				ClientSecret: "secret",
+				Scopes:       []string{"openid", "profile"},
+			},
+		}
+		s := auth.New(cfg)
+		if err := s.Init(); err != nil {
+			t.Fatalf("%s: error init auth service: %s\n", tt.grant, err)
+		}
+
+		if err := s.Login(ctx, browserFollowingRedirects(t)); err != nil {
+			t.Fatalf("%s: error logging in: %s\n", tt.grant, err)
+		}
+
+		first, err := s.Token(ctx.Ctx)
+		if err != nil || first == "" {
+			t.Fatalf("%s: expected token got %v\n", tt.grant, err)
+		}
+
+		// expires within the skew, so we should get a refreshed token
+		second, err := s.Token(ctx.Ctx)
+		if err != nil || second == first {
+			t.Fatalf("%s: expected refreshed token got %s %v\n", tt.grant, second, err)
+		}
+	}
+}
+
+func TestOAuth2PasswordGrantFails(t *testing.T) {
+	srv := httptest.NewServer(&testIdP{challenges: make(map[string]string)})
+	defer srv.Close()
+
+	cfg := &browserk.Config{
+		AuthType:    browserk.OAuth2,
+		Credentials: &browserk.Credentials{Username: "admin", Password: "wrong"},
+		OAuth: &browserk.OAuthConfig{
+			Grant:    browserk.GrantPassword,
+			TokenURL: srv.URL + "/token",
+			ClientID: "client",
+		},
+	}
+	s := auth.New(cfg)
+	if err := s.Init(); err != nil {
+		t.Fatalf("error init auth service: %s\n", err)
+	}
+
+	ctx := &browserk.Context{Ctx: context.Background(), Log: &log.Logger}
+	if err := s.Login(ctx, mock.MakeMockBrowser()); err == nil {
+		t.Fatalf("expected invalid credentials to fail")
+	}
+}
+
+// browserFollowingRedirects loads urls with a real http client, stopping at the first
+// redirect so we end up 'on' the redirect uri like a browser would
+func browserFollowingRedirects(t *testing.T) *mock.Browser {
+	currentURL := "about:blank"
+	client := &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	b := mock.MakeMockBrowser()
+	b.GetURLFn = func() (string, error) {
+		return currentURL, nil
+	}
+	b.ExecuteActionFn = func(ctx context.Context, act *browserk.Action) ([]byte, bool, error) {
+		if act.Type != browserk.ActLoadURL {
+			return nil, false, nil
+		}
+		resp, err := client.Get(string(act.Input))
+		if err != nil {
+			t.Fatalf("error loading %s: %s\n", act.Input, err)
+		}
+		resp.Body.Close()
+		currentURL = string(act.Input)
+		if location := resp.Header.Get("Location"); location != "" {
+			currentURL = location
+		}
+		return nil, true, nil
+	}
+	return b
+}
@@ -0,0 +1,173 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"time"
+
+	"github.com/dop251/goja"
+	"github.com/dop251/goja_nodejs/console"
+	"github.com/dop251/goja_nodejs/require"
+	"github.com/pkg/errors"
+	"gitlab.com/browserker/browserk"
+)
+
+// runLogin calls the Login function the script must define
+const runLogin = `Login(browser, credentials);`
+
+// ScriptBrowser is the browser handle exposed to login scripts as `browser`, its methods are
+// lower cased (browser.navigate, browser.waitFor etc). Any returned error is thrown as an
+// exception inside of the script.
+type ScriptBrowser struct {
+	ctx     context.Context
+	browser browserk.Browser
+}
+
+// NewScriptBrowser wraps the browser for use in a login script
+func NewScriptBrowser(ctx context.Context, browser browserk.Browser) *ScriptBrowser {
+	return &ScriptBrowser{ctx: ctx, browser: browser}
+}
+
+// Navigate to the url, waiting for the page to load
+func (b *ScriptBrowser) Navigate(url string) error {
+	_, _, err := b.browser.ExecuteAction(b.ctx, browserk.NewLoadURLAction(url))
+	return err
+}
+
+// Click the first element matching the css selector
+func (b *ScriptBrowser) Click(selector string) error {
+	ele, err := b.find(selector)
+	if err != nil {
+		return err
+	}
+	_, _, err = b.browser.ExecuteAction(b.ctx, &browserk.Action{Type: browserk.ActLeftClick, Element: ele})
+	return err
+}
+
+// Type the text into the first element matching the css selector
+func (b *ScriptBrowser) Type(selector, text string) error {
+	ele, err := b.find(selector)
+	if err != nil {
+		return err
+	}
+	act := browserk.NewKeyboardAction(browserk.ActSendKeys, ele, &browserk.KeyboardInput{Keys: text})
+	_, _, err = b.browser.ExecuteAction(b.ctx, act)
+	return err
+}
+
+// WaitFor an element matching the css selector to exist, for at most timeout milliseconds
+func (b *ScriptBrowser) WaitFor(selector string, timeout int64) error {
+	ticker := time.NewTicker(250 * time.Millisecond)
+	defer ticker.Stop()
+	timer := time.NewTimer(time.Duration(timeout) * time.Millisecond)
+	defer timer.Stop()
+
+	for {
+		b.browser.RefreshDocument()
+		if eles, err := b.browser.FindElements(selector); err == nil && len(eles) > 0 {
+			return nil
+		}
+
+		select {
+		case <-ticker.C:
+		case <-timer.C:
+			return errors.Errorf("timed out waiting for %s", selector)
+		case <-b.ctx.Done():
+			return b.ctx.Err()
+		}
+	}
+}
+
+// Evaluate the javascript in the page, returning the result
+func (b *ScriptBrowser) Evaluate(js string) (interface{}, error) {
+	result, _, err := b.browser.ExecuteAction(b.ctx, &browserk.Action{Type: browserk.ActExecuteJS, Input: []byte(js)})
+	if err != nil || len(result) == 0 {
+		return nil, err
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(result, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// URL the browser is currently on
+func (b *ScriptBrowser) URL() (string, error) {
+	return b.browser.GetURL()
+}
+
+// Cookies currently set in the browser
+func (b *ScriptBrowser) Cookies() ([]*browserk.Cookie, error) {
+	return b.browser.GetCookies()
+}
+
+// Wait for ms milliseconds
+func (b *ScriptBrowser) Wait(ms int64) error {
+	timer := time.NewTimer(time.Duration(ms) * time.Millisecond)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-b.ctx.Done():
+		return b.ctx.Err()
+	}
+}
+
+func (b *ScriptBrowser) find(selector string) (*browserk.HTMLElement, error) {
+	b.browser.RefreshDocument()
+	eles, err := b.browser.FindElements(selector)
+	if err != nil {
+		return nil, err
+	}
+	if len(eles) == 0 {
+		return nil, errors.Errorf("no element found for %s", selector)
+	}
+	return eles[0], nil
+}
+
+// scriptLogin runs the configured login script, a Login function returning false
+// is treated as a failed login.
+func (s *Service) scriptLogin(c *browserk.Context, browser browserk.Browser) error {
+	src, err := ioutil.ReadFile(s.cfg.AuthScript)
+	if err != nil {
+		return errors.Wrap(err, "reading auth script")
+	}
+
+	vm := goja.New()
+	// scripts call browser.navigate/click/type/waitFor/evaluate and read credentials.username etc
+	vm.SetFieldNameMapper(goja.UncapFieldNameMapper())
+	new(require.Registry).Enable(vm)
+	console.Enable(vm)
+
+	if _, err := vm.RunScript(s.cfg.AuthScript, string(src)); err != nil {
+		return errors.Wrap(err, "running auth script")
+	}
+
+	creds := s.cfg.Credentials
+	if creds == nil {
+		creds = &browserk.Credentials{}
+	}
+	vm.Set("browser", NewScriptBrowser(c.Ctx, browser))
+	vm.Set("credentials", creds)
+
+	c.Log.Info().Str("script", s.cfg.AuthScript).Msg("running login script")
+	result, err := vm.RunString(runLogin)
+	if err != nil {
+		return errors.Wrap(err, "login script failed")
+	}
+
+	if result != nil && result.ExportType() != nil && !result.ToBoolean() {
+		return ErrLoginFailed
+	}
+
+	if s.cfg.LoggedInElement != "" || s.cfg.LoggedInCookie != "" {
+		if !s.waitLoggedIn(c, browser, "") {
+			return ErrLoginFailed
+		}
+	}
+	c.Log.Info().Msg("login succeeded")
+	return nil
+}
@@ -0,0 +1,65 @@
+package auth_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rs/zerolog/log"
+	"gitlab.com/browserker/browserk"
+	"gitlab.com/browserker/mock"
+	"gitlab.com/browserker/scanner/auth"
+)
+
+func TestScriptLogin(t *testing.T) {
+	cfg := &browserk.Config{
+		AuthType:    browserk.Script,
+		AuthScript:  "testdata/login.js",
+		Credentials: &browserk.Credentials{Username: "admin", Password: "hunter2"},
+	}
+
+	typed := make(map[string]string)
+	b := mock.MakeMockBrowser()
+	b.FindElementsFn = func(querySelector string) ([]*browserk.HTMLElement, error) {
+		ele := &browserk.HTMLElement{Type: browserk.INPUT, Attributes: map[string]string{"id": querySelector[1:]}}
+		return []*browserk.HTMLElement{ele}, nil
+	}
+	b.ExecuteActionFn = func(ctx context.Context, act *browserk.Action) ([]byte, bool, error) {
+		switch act.Type {
+		case browserk.ActSendKeys:
+			typed[act.Element.Attributes["id"]] = act.KeyboardInput().Keys
+		case browserk.ActExecuteJS:
+			return []byte(`"Welcome"`), false, nil
+		}
+		return nil, false, nil
+	}
+
+	s := auth.New(cfg)
+	if err := s.Init(); err != nil {
+		t.Fatalf("error init auth service: %s\n", err)
+	}
+
+	if !s.MustLogin() {
+		t.Fatalf("expected script auth to require login")
+	}
+
+	ctx := &browserk.Context{Ctx: context.Background(), Log: &log.Logger}
+	if err := s.Login(ctx, b); err != nil {
+		t.Fatalf("expected login to succeed: %s\n", err)
+	}
+
+	if typed["username"] != "admin" || typed["password"] != "hunter2" {
+		t.Fatalf("expected credentials to be typed, got %v\n", typed)
+	}
+
+	if s.Session() == nil {
+		t.Fatalf("expected session to be captured")
+	}
+
+	// title no longer matches, script returns false
+	b.ExecuteActionFn = func(ctx context.Context, act *browserk.Action) ([]byte, bool, error) {
+		return []byte(`"Sign in"`), false, nil
+	}
+	if err := s.Login(ctx, b); err != auth.ErrLoginFailed {
+		t.Fatalf("expected login to fail, got %v\n", err)
+	}
+}
@@ -0,0 +1,11 @@
+// Login is called with the browser handle and configured credentials,
+// returning false marks the login as failed.
+function Login(browser, credentials) {
+    browser.navigate("http://localhost/sso");
+    browser.waitFor("#username", 1000);
+    browser.type("#username", credentials.username);
+    browser.click("#next");
+    browser.type("#password", credentials.password);
+    browser.click("#submit");
+    return browser.evaluate("document.title") === "Welcome";
+}
@@ -24,6 +24,18 @@ type Container struct {
 
 	consoleLock   sync.RWMutex
 	consoleEvents []*browserk.ConsoleEvent
+
+	blockedLock sync.RWMutex
+	blocked     []*browserk.BlockedRequestEvent
+
+	authLock       sync.RWMutex
+	authChallenges []*browserk.AuthChallengeEvent
+
+	urlLock    sync.RWMutex
+	urlChanges []*browserk.URLChangeEvent
+
+	scriptLock sync.RWMutex
+	scripts    []*browserk.ScriptEvent
 }
 
 // NewContainer for holding request/responses, storage and console events
@@ -60,6 +72,74 @@ func (c *Container) AddConsoleEvent(evt *browserk.ConsoleEvent) {
 	c.consoleLock.Unlock()
 }
 
+// AddBlockedRequest to the container
+func (c *Container) AddBlockedRequest(evt *browserk.BlockedRequestEvent) {
+	c.blockedLock.Lock()
+	c.blocked = append(c.blocked, evt)
+	c.blockedLock.Unlock()
+}
+
+// GetBlockedRequests and clear the container
+func (c *Container) GetBlockedRequests() []*browserk.BlockedRequestEvent {
+	c.blockedLock.Lock()
+	evts := make([]*browserk.BlockedRequestEvent, len(c.blocked))
+	copy(evts, c.blocked)
+	c.blocked = make([]*browserk.BlockedRequestEvent, 0)
+	c.blockedLock.Unlock()
+	return evts
+}
+
+// AddAuthChallenge to the container
+func (c *Container) AddAuthChallenge(evt *browserk.AuthChallengeEvent) {
+	c.authLock.Lock()
+	c.authChallenges = append(c.authChallenges, evt)
+	c.authLock.Unlock()
+}
+
+// GetAuthChallenges and clear the container
+func (c *Container) GetAuthChallenges() []*browserk.AuthChallengeEvent {
+	c.authLock.Lock()
+	evts := make([]*browserk.AuthChallengeEvent, len(c.authChallenges))
+	copy(evts, c.authChallenges)
+	c.authChallenges = make([]*browserk.AuthChallengeEvent, 0)
+	c.authLock.Unlock()
+	return evts
+}
+
+// AddURLChange to the container
+func (c *Container) AddURLChange(evt *browserk.URLChangeEvent) {
+	c.urlLock.Lock()
+	c.urlChanges = append(c.urlChanges, evt)
+	c.urlLock.Unlock()
+}
+
+// GetURLChanges and clear the container
+func (c *Container) GetURLChanges() []*browserk.URLChangeEvent {
+	c.urlLock.Lock()
+	evts := make([]*browserk.URLChangeEvent, len(c.urlChanges))
+	copy(evts, c.urlChanges)
+	c.urlChanges = make([]*browserk.URLChangeEvent, 0)
+	c.urlLock.Unlock()
+	return evts
+}
+
+// AddScript to the container
+func (c *Container) AddScript(evt *browserk.ScriptEvent) {
+	c.scriptLock.Lock()
+	c.scripts = append(c.scripts, evt)
+	c.scriptLock.Unlock()
+}
+
+// GetScripts and clear the container
+func (c *Container) GetScripts() []*browserk.ScriptEvent {
+	c.scriptLock.Lock()
+	evts := make([]*browserk.ScriptEvent, len(c.scripts))
+	copy(evts, c.scripts)
+	c.scripts = make([]*browserk.ScriptEvent, 0)
+	c.scriptLock.Unlock()
+	return evts
+}
+
 // GetConsoleEvents and clear the container
 func (c *Container) GetConsoleEvents() []*browserk.ConsoleEvent {
 	c.consoleLock.Lock()
@@ -20,6 +20,7 @@ func ElementToHTMLElement(ele *Element) *browserk.HTMLElement {
 	tag, _ := ele.GetTagName()
 	b.Attributes, _ = ele.GetAttributes()
 	b.NodeDepth = ele.Depth()
+	b.Path = ele.Path()
 	listeners, err := ele.GetEventListeners()
 	b.InnerText = ele.GetInnerText()
 
@@ -47,6 +48,7 @@ func ElementToHTMLFormElement(ele *Element) *browserk.HTMLFormElement {
 	b := &browserk.HTMLFormElement{Events: make(map[string]browserk.HTMLEventType, 0)}
 	b.Attributes, _ = ele.GetAttributes()
 	b.NodeDepth = ele.Depth()
+	b.Path = ele.Path()
 	listeners, err := ele.GetEventListeners()
 	if err == nil {
 		for _, listener := range listeners {
@@ -108,6 +110,8 @@ func GCDFetchRequestToIntercepted(m *gcdapi.FetchRequestPausedEvent, container *
 	req := &gcdapi.NetworkRequest{}
 	if r != nil {
 		req = r.Request
+	} else if p.Request != nil {
+		req = p.Request
 	}
 	headers := make([]*gcdapi.FetchHeaderEntry, 0)
 	if p.Request != nil && p.Request.Headers != nil {
@@ -193,6 +197,34 @@ func GCDCookieToBrowserk(gcdCookie []*gcdapi.NetworkCookie) []*browserk.Cookie {
 	return cookies
 }
 
+// BrowserkCookieToGCD converts cookies to their set cookie params, cookies without a domain are
+// associated with url
+func BrowserkCookieToGCD(cookies []*browserk.Cookie, url string) []*gcdapi.NetworkCookieParam {
+	if cookies == nil {
+		return nil
+	}
+	params := make([]*gcdapi.NetworkCookieParam, len(cookies))
+	for i, c := range cookies {
+		params[i] = &gcdapi.NetworkCookieParam{
+			Name:     c.Name,
+			Value:    c.Value,
+			Domain:   c.Domain,
+			Path:     c.Path,
+			Secure:   c.Secure,
+			HttpOnly: c.HTTPOnly,
+			SameSite: c.SameSite,
+			Priority: c.Priority,
+		}
+		if c.Domain == "" {
+			params[i].Url = url
+		}
+		if !c.Session {
+			params[i].Expires = c.Expires
+		}
+	}
+	return params
+}
+
 // RedirectResponseToNetworkResponse NetworkRequestWillBeSentEvent (RedirectResponse) -> NetworkResponseReceivedEvent
 func RedirectResponseToNetworkResponse(req *gcdapi.NetworkRequestWillBeSentEvent) *gcdapi.NetworkResponseReceivedEvent {
 	p := req.Params
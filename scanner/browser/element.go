@@ -68,6 +68,7 @@ type Element struct {
 	childNodeCount int               // the number of children this element has
 	nodeType       int               // the DOM nodeType
 	depth          int               // depth of the node in the document
+	path           []string          // frames/shadow roots this element is inside of
 	tab            *Tab              // reference to the containing tab
 	node           *gcdapi.DOMNode   // the dom node, taken from the document
 	readyGate      chan struct{}     // gate to close upon recieving all information from the debugger service
@@ -159,6 +160,19 @@ func (e *Element) Depth() int {
 	return e.depth
 }
 
+// Path of frames and shadow roots (outermost first) this element is inside of
+func (e *Element) Path() []string {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+	return e.path
+}
+
+func (e *Element) setPath(path []string) {
+	e.lock.Lock()
+	e.path = path
+	e.lock.Unlock()
+}
+
 // WaitForReady If we are ready, just return, if we are not, wait for the readyGate
 // to be closed or for the timeout timer to fired.
 func (e *Element) WaitForReady() error {
@@ -584,6 +598,51 @@ func (e *Element) DoubleClick() error {
 	return e.tab.DoubleClick(float64(x), float64(y))
 }
 
+// RightClick the center of the element.
+func (e *Element) RightClick() error {
+	x, y, err := e.getCenter()
+	if err != nil {
+		return err
+	}
+
+	return e.tab.RightClick(float64(x), float64(y))
+}
+
+// MouseWheel scrolls by deltaX, deltaY pixels with the mouse over the center of the element,
+// scrolling the element itself if it's scrollable or the closest scrollable ancestor.
+func (e *Element) MouseWheel(deltaX, deltaY float64) error {
+	x, y, err := e.getCenter()
+	if err != nil {
+		return err
+	}
+
+	return e.tab.MouseWheel(float64(x), float64(y), deltaX, deltaY)
+}
+
+// Scroll the element into view then scroll its content down by the element's height,
+// for scrollable containers (feeds, lazy loaded lists)
+func (e *Element) Scroll() error {
+	if err := e.ScrollTo(); err != nil {
+		return err
+	}
+
+	points, err := e.Dimensions()
+	if err != nil {
+		return err
+	}
+
+	x, y, err := centroid(points)
+	if err != nil {
+		return err
+	}
+
+	delta := boxHeight(points)
+	if delta <= 0 {
+		delta = defaultWheelDelta
+	}
+	return e.tab.MouseWheel(float64(x), float64(y), 0, delta)
+}
+
 // Focus on the element.
 func (e *Element) Focus() error {
 	e.lock.RLock()
@@ -678,6 +737,30 @@ func (e *Element) SendRawKeys(keys string) error {
 	return err
 }
 
+// SendKeyEvents focuses the element and dispatches the key events for keys (text and/or keymap keys)
+// with the modifiers held. down sends keyDown/char events and up sends keyUp events.
+func (e *Element) SendKeyEvents(keys string, modifiers keymap.Modifier, down, up bool) error {
+	e.Focus()
+	err := e.Click()
+	if err != nil {
+		return err
+	}
+	for _, c := range keys {
+		for _, key := range keymap.KeyEncodeModified(c, modifiers) {
+			isUp := key.TheType == keymap.KeyUp.String()
+			if (isUp && !up) || (!isUp && !down) {
+				continue
+			}
+
+			if _, err = e.tab.t.Input.DispatchKeyEventWithParams(key); err != nil {
+				return err
+			}
+			time.Sleep(time.Millisecond * 70) // small delay inbetween key presses
+		}
+	}
+	return nil
+}
+
 // String gnarly output mode activated
 func (e *Element) String() string {
 	e.lock.RLock()
@@ -713,3 +796,20 @@ func centroid(points []float64) (int, int, error) {
 	}
 	return x / (pointLen / 2), y / (pointLen / 2), nil
 }
+
+// boxHeight of the quad points returned from a box model
+func boxHeight(points []float64) float64 {
+	if len(points) < 2 || len(points)%2 != 0 {
+		return 0
+	}
+	min, max := points[1], points[1]
+	for i := 3; i < len(points); i = i + 2 {
+		if points[i] < min {
+			min = points[i]
+		}
+		if points[i] > max {
+			max = points[i]
+		}
+	}
+	return max - min
+}
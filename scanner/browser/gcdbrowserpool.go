@@ -211,7 +211,7 @@ func (b *GCDBrowserPool) closeAndCreateBrowser(port string, doneCh chan struct{}
 	close(doneCh)
 }
 
-// Take a browser
+// Take a browser, applying the context's State (if any) before it is handed out
 func (b *GCDBrowserPool) Take(ctx *browserk.Context) (browserk.Browser, string, error) {
 	var br *gcd.Gcd
 
@@ -230,6 +230,13 @@ func (b *GCDBrowserPool) Take(ctx *browserk.Context) (browserk.Browser, string,
 		return nil, "", fmt.Errorf("failed to aquire valid tab from browser")
 	}
 	gtab := NewTab(ctx, br, t)
+	if ctx.State != nil {
+		if err := gtab.ApplyState(ctx.State); err != nil {
+			gtab.Close()
+			b.Return(ctx.Ctx, br.Port())
+			return nil, "", err
+		}
+	}
 	return gtab, br.Port(), nil
 }
 
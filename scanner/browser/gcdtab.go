@@ -49,8 +49,12 @@ type Tab struct {
 	domChangeHandler      DomChangeHandlerFunc   // allows the caller to be notified of DOM change events.
 	docWasUpdated         atomic.Value           // for tracking if an execution caused a new page load/transition
 
-	frameMutex *sync.RWMutex
-	frames     map[string]int // frames
+	frameMutex  *sync.RWMutex
+	frames      map[string]int // frames
+	shadowRoots map[int]int    // host nodeID -> open/closed shadow root nodeID
+
+	authMutex    *sync.Mutex
+	authAttempts map[string]struct{} // in flight requests we already answered a challenge for
 }
 
 // NewTab to use
@@ -66,7 +70,10 @@ func NewTab(bctx *browserk.Context, gcdBrowser *gcd.Gcd, tab *gcd.ChromeTarget)
 	t.elements = make(map[int]*Element)
 
 	t.frames = make(map[string]int)
+	t.shadowRoots = make(map[int]int)
 	t.frameMutex = &sync.RWMutex{}
+	t.authMutex = &sync.Mutex{}
+	t.authAttempts = make(map[string]struct{})
 
 	t.nodeChange = make(chan *NodeChangeEvent)
 	t.navigationCh = make(chan int, 1)  // for signaling navigation complete
@@ -104,6 +111,7 @@ func (t *Tab) Close() {
 func (t *Tab) ExecuteAction(ctx context.Context, act *browserk.Action) ([]byte, bool, error) {
 	var err error
 	var ele *Element
+	var result []byte
 	causedLoad := false
 	// Call JSBefore hooks
 	t.ctx.NextJSBefore(t)
@@ -113,7 +121,8 @@ func (t *Tab) ExecuteAction(ctx context.Context, act *browserk.Action) ([]byte,
 
 	errMsg := fmt.Sprintf("unable to find element for %s", browserk.ActionTypeMap[act.Type])
 
-	if act.Type > browserk.ActExecuteJS && act.Type < browserk.ActFillForm {
+	// scrolls without an element scroll the page
+	if act.Type > browserk.ActExecuteJS && act.Type < browserk.ActFillForm && !(act.Type == browserk.ActScroll && act.Element == nil) {
 		ele, err = t.FindByHTMLElement(act.Element)
 		if err != nil {
 			t.ctx.Log.Warn().Err(err).Msg(errMsg)
@@ -126,7 +135,10 @@ func (t *Tab) ExecuteAction(ctx context.Context, act *browserk.Action) ([]byte,
 	case browserk.ActLoadURL:
 		t.Navigate(ctx, string(act.Input))
 	case browserk.ActExecuteJS:
-		t.InjectJS(string(act.Input))
+		var value interface{}
+		if value, err = t.InjectJS(string(act.Input)); err == nil {
+			result, err = json.Marshal(value)
+		}
 	case browserk.ActLeftClick, browserk.ActLeftClickDown, browserk.ActLeftClickUp, browserk.ActDoubleClick:
 		ele.ScrollTo()
 		if act.Type == browserk.ActDoubleClick {
@@ -141,12 +153,31 @@ func (t *Tab) ExecuteAction(ctx context.Context, act *browserk.Action) ([]byte,
 		t.ctx.Log.Debug().Str("action", act.String()).Msg("clicked element")
 	case browserk.ActFillForm:
 		t.ctx.Log.Info().Str("action", act.String()).Msg("fill form action executing...")
-		t.FillForm(act)
+		err = t.FillForm(act)
 	case browserk.ActRightClick:
-	case browserk.ActScroll:
 		ele.ScrollTo()
+		if err = ele.RightClick(); err != nil {
+			t.ctx.Log.Warn().Err(err).Msg(errMsg)
+		}
+	case browserk.ActScroll:
+		if ele == nil {
+			err = t.ScrollPage()
+		} else {
+			err = ele.Scroll()
+		}
 	case browserk.ActSendKeys, browserk.ActKeyUp, browserk.ActKeyDown:
-		ele.SendRawKeys(keymap.Enter)
+		input := act.KeyboardInput()
+		if input.Keys == "" {
+			input.Keys = keymap.Enter
+		}
+
+		err = ele.SendKeyEvents(input.Keys, keymap.Modifier(input.Modifiers), act.Type != browserk.ActKeyUp, act.Type != browserk.ActKeyDown)
+		if err != nil {
+			t.ctx.Log.Warn().Err(err).Msg(errMsg)
+		} else if act.Type == browserk.ActSendKeys && len(act.Input) > 0 {
+			// type-ahead/search-as-you-type requests are usually debounced
+			t.waitForKeyInput(ctx)
+		}
 	case browserk.ActHover:
 		ele.ScrollTo()
 		ele.MouseOver()
@@ -160,7 +191,10 @@ func (t *Tab) ExecuteAction(ctx context.Context, act *browserk.Action) ([]byte,
 		ele.MouseOver()
 		t.MoveMouse(0, 0)
 	case browserk.ActMouseWheel:
-
+		ele.ScrollTo()
+		if err = ele.MouseWheel(0, defaultWheelDelta); err != nil {
+			t.ctx.Log.Warn().Err(err).Msg(errMsg)
+		}
 	}
 	// add small delay after action
 	timer := time.NewTimer(time.Millisecond * 200)
@@ -181,7 +215,18 @@ func (t *Tab) ExecuteAction(ctx context.Context, act *browserk.Action) ([]byte,
 		causedLoad = docUpdated
 	}
 
-	return nil, causedLoad, err
+	return result, causedLoad, err
+}
+
+// waitForKeyInput gives debounced requests fired by typing a chance to be sent
+func (t *Tab) waitForKeyInput(ctx context.Context) {
+	timer := time.NewTimer(keyInputWait)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
 }
 
 // FillForm for an action
@@ -565,6 +610,37 @@ func (t *Tab) GetCookies() ([]*browserk.Cookie, error) {
 	return GCDCookieToBrowserk(cookies), nil
 }
 
+// ApplyState sets the cookies and registers the origin storage to be applied to the first
+// document of each origin, must be called before navigating
+func (t *Tab) ApplyState(state *browserk.BrowserState) error {
+	if len(state.Cookies) > 0 {
+		if _, err := t.t.Network.SetCookies(BrowserkCookieToGCD(state.Cookies, "")); err != nil {
+			return errors.Wrap(err, "setting state cookies")
+		}
+	}
+
+	for _, origin := range state.Origins {
+		script, err := StorageScript(origin)
+		if err != nil {
+			return err
+		}
+		if _, err := t.t.Page.AddScriptToEvaluateOnNewDocument(script, ""); err != nil {
+			return errors.Wrap(err, "adding storage script")
+		}
+	}
+	return nil
+}
+
+// SetCookies in the browser, cookies without a domain will be set for the current url
+func (t *Tab) SetCookies(cookies []*browserk.Cookie) error {
+	if len(cookies) == 0 {
+		return nil
+	}
+	currentURL, _ := t.GetURL()
+	_, err := t.t.Network.SetCookies(BrowserkCookieToGCD(cookies, currentURL))
+	return err
+}
+
 // GetStorageEvents and clear the container
 func (t *Tab) GetStorageEvents() []*browserk.StorageEvent {
 	return t.container.GetStorageEvents()
@@ -575,6 +651,36 @@ func (t *Tab) GetConsoleEvents() []*browserk.ConsoleEvent {
 	return t.container.GetConsoleEvents()
 }
 
+// GetBlockedRequests and clear the container
+func (t *Tab) GetBlockedRequests() []*browserk.BlockedRequestEvent {
+	return t.container.GetBlockedRequests()
+}
+
+// GetAuthChallenges (basic/digest/ntlm) and clear the container
+func (t *Tab) GetAuthChallenges() []*browserk.AuthChallengeEvent {
+	return t.container.GetAuthChallenges()
+}
+
+// GetURLChanges (client side route changes) and clear the container
+func (t *Tab) GetURLChanges() []*browserk.URLChangeEvent {
+	return t.container.GetURLChanges()
+}
+
+// GetScripts parsed since the last call and clear the container, the source of each
+// script is retrieved from the debugger
+func (t *Tab) GetScripts() []*browserk.ScriptEvent {
+	scripts := t.container.GetScripts()
+	for _, script := range scripts {
+		source, err := t.GetScriptSource(script.ScriptID)
+		if err != nil {
+			t.ctx.Log.Debug().Err(err).Str("url", script.URL).Msg("failed to get script source")
+			continue
+		}
+		script.Source = source
+	}
+	return scripts
+}
+
 // EvaluateScript in the global context.
 func (t *Tab) EvaluateScript(scriptSource string) (*gcdapi.RuntimeRemoteObject, error) {
 	return t.evaluateScript(scriptSource, false)
@@ -691,7 +797,12 @@ func (t *Tab) getDocument() (*Element, error) {
 	}
 	t.setTopNodeID(doc.NodeId)
 	t.ctx.Log.Debug().Msgf("getDocument doc id is now: %d", t.getTopNodeID())
-	t.addNodes(doc, 0)
+	t.frameMutex.Lock()
+	t.frames = make(map[string]int)
+	t.shadowRoots = make(map[int]int)
+	t.frameMutex.Unlock()
+
+	t.addNodes(doc, 0, nil)
 	eleDoc, _ := t.getElement(doc.NodeId)
 	return eleDoc, nil
 }
@@ -792,13 +903,44 @@ func (t *Tab) GetElementsBySelector(selector string) ([]*Element, error) {
 	for _, id := range frameNodeIDs {
 		frameElements, err := t.GetDocumentElementsBySelector(id, selector)
 		if err != nil {
-			t.ctx.Log.Warn().Msg("failed to search frame for elements")
+			// frames may be detached while we walk them, the rest of the document is still searched
+			t.ctx.Log.Warn().Err(err).Int("node_id", id).Str("selector", selector).Msg("failed to search frame for elements")
 			continue
 		}
 		t.ctx.Log.Debug().Int("found", len(frameElements)).Str("selector", selector).Msg("found in frames")
 		elements = append(elements, frameElements...)
 	}
-	return elements, err
+
+	// and open/closed shadow roots
+	for _, id := range t.getShadowRootNodeIDs() {
+		shadowElements, err := t.GetDocumentElementsBySelector(id, selector)
+		if err != nil {
+			t.ctx.Log.Warn().Err(err).Int("node_id", id).Str("selector", selector).Msg("failed to search shadow root for elements")
+			continue
+		}
+		t.ctx.Log.Debug().Int("found", len(shadowElements)).Str("selector", selector).Msg("found in shadow roots")
+		elements = append(elements, shadowElements...)
+	}
+	return elements, nil
+}
+
+// elementPath appends the step for the frame/shadow root hosted by host to path
+func elementPath(path []string, step string, host *Element) []string {
+	tag, _ := host.GetTagName()
+	attributes, _ := host.GetAttributes()
+	newPath := make([]string, len(path), len(path)+1)
+	copy(newPath, path)
+	return append(newPath, step+browserk.ElementPathHost(tag, attributes))
+}
+
+func (t *Tab) getShadowRootNodeIDs() []int {
+	nodeIDs := make([]int, 0)
+	t.frameMutex.RLock()
+	for _, v := range t.shadowRoots {
+		nodeIDs = append(nodeIDs, v)
+	}
+	t.frameMutex.RUnlock()
+	return nodeIDs
 }
 
 func (t *Tab) getFrameNodeIDs() []int {
@@ -974,7 +1116,7 @@ func (t *Tab) documentUpdated() {
 
 // Ask the debugger service for child nodes.
 func (t *Tab) requestChildNodes(nodeID, depth int) {
-	_, err := t.t.DOM.RequestChildNodes(nodeID, depth, false)
+	_, err := t.t.DOM.RequestChildNodes(nodeID, depth, true)
 	if err != nil {
 		t.ctx.Log.Debug().Msgf("error requesting child nodes: %s\n", err)
 	}
@@ -1000,11 +1142,12 @@ func (t *Tab) getElement(nodeID int) (*Element, bool) {
 }
 
 // Safely adds the nodes in the document to our list of elements
-// iterates over children and contentdocuments (if they exist)
+// iterates over children, shadow roots and contentdocuments (if they exist)
 // Calls requestchild nodes for each node so we can receive setChildNode
-// events for even more nodes
-func (t *Tab) addNodes(node *gcdapi.DOMNode, depth int) {
+// events for even more nodes. path is the frames/shadow roots the node is in
+func (t *Tab) addNodes(node *gcdapi.DOMNode, depth int, path []string) {
 	newEle := t.nodeToElement(node, depth)
+	newEle.setPath(path)
 
 	t.eleMutex.Lock()
 	t.elements[newEle.ID] = newEle
@@ -1013,7 +1156,7 @@ func (t *Tab) addNodes(node *gcdapi.DOMNode, depth int) {
 	if node.Children != nil {
 		// add child nodes
 		for _, v := range node.Children {
-			t.addNodes(v, depth+1)
+			t.addNodes(v, depth+1, path)
 		}
 	}
 
@@ -1024,12 +1167,24 @@ func (t *Tab) addNodes(node *gcdapi.DOMNode, depth int) {
 		t.baseHref.Store(newEle.GetAttribute("href"))
 	}
 
+	for _, root := range node.ShadowRoots {
+		// user agent shadow roots are the browsers own (input/video controls etc)
+		if root.ShadowRootType == "user-agent" {
+			continue
+		}
+		t.frameMutex.Lock()
+		t.shadowRoots[node.NodeId] = root.NodeId
+		t.frameMutex.Unlock()
+
+		t.addNodes(root, depth+1, elementPath(path, browserk.ShadowPathStep, newEle))
+	}
+
 	if node.ContentDocument != nil {
 		t.frameMutex.Lock()
 		t.frames[node.FrameId] = node.ContentDocument.NodeId
 		t.frameMutex.Unlock()
 
-		t.addNodes(node.ContentDocument, depth+1)
+		t.addNodes(node.ContentDocument, depth+1, elementPath(path, browserk.FramePathStep, newEle))
 	}
 	t.lastNodeChangeTimeVal.Store(time.Now())
 }
@@ -1145,7 +1300,7 @@ func (t *Tab) handleSetChildNodes(parentNodeID int, nodes []*gcdapi.DOMNode) {
 	parent, ok := t.getElementByNodeID(parentNodeID)
 	depth := parent.Depth() + 1
 	for _, node := range nodes {
-		t.addNodes(node, depth)
+		t.addNodes(node, depth, parent.Path())
 	}
 	if ok {
 		if err := parent.WaitForReady(); err == nil {
@@ -1164,7 +1319,7 @@ func (t *Tab) handleChildNodeInserted(parentNodeID int, node *gcdapi.DOMNode) {
 	}
 	parent, _ := t.getElementByNodeID(parentNodeID)
 	depth := parent.Depth() + 1
-	t.addNodes(node, depth)
+	t.addNodes(node, depth, parent.Path())
 
 	// make sure we have the parent before we add children
 	if err := parent.WaitForReady(); err == nil {
@@ -1276,7 +1431,7 @@ func (t *Tab) subscribeBrowserEvents(ctx *browserk.Context, intercept bool) {
 		}
 		t.t.Fetch.EnableWithParams(&gcdapi.FetchEnableParams{
 			Patterns:           patterns,
-			HandleAuthRequests: false,
+			HandleAuthRequests: true,
 		})
 		t.subscribeInterception(ctx)
 	}
@@ -1303,4 +1458,6 @@ func (t *Tab) subscribeBrowserEvents(ctx *browserk.Context, intercept bool) {
 	t.subscribeStorageEvents()
 	t.subscribeConsoleEvents()
 	t.subscribeDialogEvents()
+	t.subscribeURLEvents()
+	t.subscribeScriptEvents()
 }
@@ -2,6 +2,7 @@ package browser_test
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"gitlab.com/browserker/browserk"
@@ -62,3 +63,78 @@ func TestActionClick(t *testing.T) {
 		t.Fatalf("expected 3 console log events, got %d\n", len(evts))
 	}
 }
+
+func TestActionClickShadowAndFrame(t *testing.T) {
+	pool := browser.NewGCDBrowserPool(1, leaser)
+	if err := pool.Init(); err != nil {
+		t.Fatalf("failed to init pool")
+	}
+	defer leaser.Cleanup()
+
+	ctx := context.Background()
+	bCtx := mock.Context(ctx)
+	p, srv := testServer()
+	defer srv.Shutdown(ctx)
+
+	url := fmt.Sprintf("http://localhost:%s/shadow.html", p)
+
+	b, _, err := pool.Take(bCtx)
+	if err != nil {
+		t.Fatalf("error taking browser: %s\n", err)
+	}
+
+	err = b.Navigate(ctx, url)
+	if err != nil {
+		t.Fatalf("error getting url %s\n", err)
+	}
+	eles, err := b.FindElements("button")
+	if err != nil {
+		t.Fatalf("error getting elements: %s\n", err)
+	}
+
+	paths := make(map[string]*browserk.HTMLElement)
+	for _, ele := range eles {
+		if len(ele.Path) != 1 {
+			t.Fatalf("expected %s to be in a frame or shadow root got path %v\n", ele.Attributes["id"], ele.Path)
+		}
+		paths[ele.Path[0]] = ele
+	}
+
+	shadow, ok := paths[browserk.ShadowPathStep+"div#host"]
+	if !ok || shadow.Attributes["id"] != "shadowbtn" {
+		t.Fatalf("expected shadow root button got %v\n", paths)
+	}
+
+	var frame *browserk.HTMLElement
+	for path, ele := range paths {
+		if strings.HasPrefix(path, browserk.FramePathStep+"iframe#fr") {
+			frame = ele
+		}
+	}
+	if frame == nil || frame.Attributes["id"] != "framebtn" {
+		t.Fatalf("expected frame button got %v\n", paths)
+	}
+
+	// replay the clicks on a freshly loaded page so the elements are found again by their path
+	err = b.Navigate(ctx, url)
+	if err != nil {
+		t.Fatalf("error getting url %s\n", err)
+	}
+	b.GetConsoleEvents()
+
+	for _, ele := range []*browserk.HTMLElement{shadow, frame} {
+		act := &browserk.Action{
+			Type:    browserk.ActLeftClick,
+			Element: ele,
+		}
+
+		if _, _, err := b.ExecuteAction(ctx, act); err != nil {
+			t.Fatalf("error clicking %s: %s\n", ele.Attributes["id"], err)
+		}
+	}
+
+	evts := b.GetConsoleEvents()
+	if len(evts) != 2 {
+		t.Fatalf("expected 2 console log events, got %d\n", len(evts))
+	}
+}
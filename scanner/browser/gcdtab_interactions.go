@@ -1,6 +1,15 @@
 package browser
 
-import "github.com/wirepair/gcd/gcdapi"
+import (
+	"time"
+
+	"github.com/wirepair/gcd/gcdapi"
+)
+
+const (
+	defaultWheelDelta = 300                    // pixels scrolled by a single mouse wheel action
+	keyInputWait      = time.Millisecond * 750 // time to wait for debounced requests after typing
+)
 
 // Click the x, y coords one time
 func (t *Tab) Click(x, y float64) error {
@@ -8,13 +17,22 @@ func (t *Tab) Click(x, y float64) error {
 }
 
 func (t *Tab) click(x, y float64, clickCount int) error {
+	return t.clickButton(x, y, "left", clickCount)
+}
+
+// RightClick the x, y coords one time
+func (t *Tab) RightClick(x, y float64) error {
+	return t.clickButton(x, y, "right", 1)
+}
+
+func (t *Tab) clickButton(x, y float64, button string, clickCount int) error {
 	// "mousePressed", "mouseReleased", "mouseMoved"
 	// enum": ["none", "left", "mIDdle", "right"]
 
 	mousePressedParams := &gcdapi.InputDispatchMouseEventParams{TheType: "mousePressed",
 		X:          x,
 		Y:          y,
-		Button:     "left",
+		Button:     button,
 		ClickCount: clickCount,
 	}
 
@@ -25,7 +43,7 @@ func (t *Tab) click(x, y float64, clickCount int) error {
 	mouseReleasedParams := &gcdapi.InputDispatchMouseEventParams{TheType: "mouseReleased",
 		X:          x,
 		Y:          y,
-		Button:     "left",
+		Button:     button,
 		ClickCount: clickCount,
 	}
 
@@ -51,6 +69,33 @@ func (t *Tab) MoveMouse(x, y float64) error {
 	return err
 }
 
+// MouseWheel scrolls by deltaX, deltaY pixels with the mouse over the x, y coords provided.
+func (t *Tab) MouseWheel(x, y, deltaX, deltaY float64) error {
+	mouseWheelParams := &gcdapi.InputDispatchMouseEventParams{TheType: "mouseWheel",
+		X:      x,
+		Y:      y,
+		DeltaX: deltaX,
+		DeltaY: deltaY,
+	}
+
+	_, err := t.t.Input.DispatchMouseEventWithParams(mouseWheelParams)
+	return err
+}
+
+// ScrollPage scrolls the page down by one viewport height by dispatching a mouse wheel event
+// in the center of the viewport.
+func (t *Tab) ScrollPage() error {
+	_, visual, _, err := t.t.Page.GetLayoutMetrics()
+	if err != nil {
+		return err
+	}
+
+	if visual.ClientWidth <= 0 || visual.ClientHeight <= 0 {
+		return t.MouseWheel(0, 0, 0, defaultWheelDelta)
+	}
+	return t.MouseWheel(visual.ClientWidth/2, visual.ClientHeight/2, 0, visual.ClientHeight)
+}
+
 // SendKeys to whatever is focused, best called from Element.SendKeys which will
 // try to focus on the element first. Use \n for Enter, \b for backspace or \t for Tab.
 func (t *Tab) SendKeys(text string) error {
@@ -271,6 +271,61 @@ func (t *Tab) subscribeConsoleEvents() {
 	})
 }
 
+// subscribeURLEvents injects our route script into every document and records the client
+// side url changes it reports
+func (t *Tab) subscribeURLEvents() {
+	t.t.Runtime.Enable()
+	if _, err := t.t.Runtime.AddBinding(urlBinding, 0); err != nil {
+		t.ctx.Log.Warn().Err(err).Msg("failed to add url change binding")
+		return
+	}
+
+	if _, err := t.t.Page.AddScriptToEvaluateOnNewDocument(routeScript, ""); err != nil {
+		t.ctx.Log.Warn().Err(err).Msg("failed to add route script")
+		return
+	}
+
+	t.t.Subscribe("Runtime.bindingCalled", func(target *gcd.ChromeTarget, payload []byte) {
+		message := &gcdapi.RuntimeBindingCalledEvent{}
+		if err := json.Unmarshal(payload, message); err != nil || message.Params.Name != urlBinding {
+			return
+		}
+
+		evt, err := ParseURLChange(message.Params.Payload)
+		if err != nil {
+			t.ctx.Log.Warn().Err(err).Msg("invalid url change")
+			return
+		}
+		// Plugin Dispatch
+		t.ctx.PluginServicer.DispatchEvent(browserk.URLChangePluginEvent(t.ctx, evt.URL, nil, evt))
+		t.container.AddURLChange(evt)
+	})
+}
+
+// subscribeScriptEvents records the javascript (not wasm) the debugger parsed, scripts without
+// a url are our own evaluated scripts and are ignored
+func (t *Tab) subscribeScriptEvents() {
+	t.t.Subscribe("Debugger.scriptParsed", func(target *gcd.ChromeTarget, payload []byte) {
+		message := &gcdapi.DebuggerScriptParsedEvent{}
+		if err := json.Unmarshal(payload, message); err != nil {
+			return
+		}
+
+		p := message.Params
+		if p.Url == "" || p.ScriptLanguage == "WebAssembly" || p.Length == 0 {
+			return
+		}
+
+		t.container.AddScript(&browserk.ScriptEvent{
+			ScriptID: p.ScriptId,
+			URL:      p.Url,
+			Hash:     p.Hash,
+			Length:   p.Length,
+			Observed: time.Now(),
+		})
+	})
+}
+
 func (t *Tab) subscribeDialogEvents() {
 	t.t.Subscribe("Page.javascriptDialogOpening", func(target *gcd.ChromeTarget, payload []byte) {
 		message := &gcdapi.PageJavascriptDialogOpeningEvent{}
@@ -385,12 +440,71 @@ func (t *Tab) subscribeInterception(ctx *browserk.Context) {
 			t.interceptedResponse(ctx, message)
 		}
 	})
+
+	t.t.Subscribe("Fetch.authRequired", func(target *gcd.ChromeTarget, payload []byte) {
+		message := &gcdapi.FetchAuthRequiredEvent{}
+		if err := json.Unmarshal(payload, message); err != nil {
+			t.ctx.Log.Error().Err(err).Msg("Fetch.authRequired event was unable to decode")
+			return
+		}
+		t.authRequired(ctx, message)
+	})
+}
+
+// authRequired answers basic/digest/ntlm challenges with the configured credentials once per request,
+// if they are rejected (or we have none) the challenge is cancelled so the browser does not hang
+func (t *Tab) authRequired(ctx *browserk.Context, message *gcdapi.FetchAuthRequiredEvent) {
+	p := message.Params
+	evt := &browserk.AuthChallengeEvent{Observed: time.Now()}
+	if p.Request != nil {
+		evt.URL = p.Request.Url
+	}
+	if p.AuthChallenge != nil {
+		evt.Source = p.AuthChallenge.Source
+		evt.Origin = p.AuthChallenge.Origin
+		evt.Scheme = p.AuthChallenge.Scheme
+		evt.Realm = p.AuthChallenge.Realm
+	}
+
+	t.authMutex.Lock()
+	_, attempted := t.authAttempts[p.RequestId]
+	t.authAttempts[p.RequestId] = struct{}{}
+	t.authMutex.Unlock()
+
+	response := &gcdapi.FetchAuthChallengeResponse{Response: "CancelAuth"}
+	if !attempted && ctx.Auth != nil {
+		if username, password, ok := ctx.Auth.HTTPCredentials(ctx, evt); ok {
+			response = &gcdapi.FetchAuthChallengeResponse{
+				Response: "ProvideCredentials",
+				Username: username,
+				Password: password,
+			}
+			evt.Answered = true
+		}
+	}
+
+	t.ctx.Log.Info().Str("url", evt.URL).Str("scheme", evt.Scheme).Str("realm", evt.Realm).Bool("answered", evt.Answered).Bool("retry", attempted).Msg("http auth challenge")
+	t.container.AddAuthChallenge(evt)
+	t.ctx.PluginServicer.DispatchEvent(browserk.AuthChallengePluginEvent(t.ctx, evt.URL, nil, evt))
+
+	if _, err := t.t.Fetch.ContinueWithAuth(p.RequestId, response); err != nil {
+		t.ctx.Log.Warn().Err(err).Str("url", evt.URL).Msg("failed to answer auth challenge")
+	}
 }
 
 func (t *Tab) interceptedRequest(ctx *browserk.Context, message *gcdapi.FetchRequestPausedEvent) {
 	// we are in a request paused event
 	modified := GCDFetchRequestToIntercepted(message, t.container)
-	ctx.NextReq(t, modified)
+	browserk.BlockExcludedRequests(ctx, t, modified)
+	if modified.Modified.FailReason == "" {
+		// copy so every request starts from the first handler
+		ctx.Copy().NextReq(t, modified)
+	}
+
+	if modified.Modified.FailReason != "" {
+		t.blockRequest(modified)
+		return
+	}
 
 	reqParams := &gcdapi.FetchContinueRequestParams{
 		RequestId: modified.RequestId,
@@ -411,8 +525,33 @@ func (t *Tab) interceptedRequest(ctx *browserk.Context, message *gcdapi.FetchReq
 	t.t.Fetch.ContinueRequestWithParams(reqParams)
 }
 
+// blockRequest fails the request and records it so the navigation result shows what we refused to send
+func (t *Tab) blockRequest(i *browserk.InterceptedHTTPRequest) {
+	evt := &browserk.BlockedRequestEvent{
+		Method:       i.Request.Method,
+		URL:          i.Request.Url,
+		ResourceType: i.ResourceType,
+		Reason:       i.Modified.FailReason,
+		Observed:     time.Now(),
+	}
+	t.container.AddBlockedRequest(evt)
+	t.ctx.Log.Info().Str("method", evt.Method).Str("url", evt.URL).Str("reason", evt.Reason).Msg("blocked request")
+
+	if _, err := t.t.Fetch.FailRequest(i.RequestId, i.Modified.FailReason); err != nil {
+		t.ctx.Log.Warn().Err(err).Str("url", evt.URL).Msg("failed to block request")
+	}
+}
+
+// authDone forgets the challenges answered for a request once it has a response (or failed)
+func (t *Tab) authDone(requestID string) {
+	t.authMutex.Lock()
+	delete(t.authAttempts, requestID)
+	t.authMutex.Unlock()
+}
+
 func (t *Tab) interceptedResponse(ctx *browserk.Context, message *gcdapi.FetchRequestPausedEvent) {
 	p := message.Params
+	t.authDone(p.RequestId)
 
 	respParams := &gcdapi.FetchFulfillRequestParams{
 		RequestId:    p.RequestId,
@@ -438,7 +577,7 @@ func (t *Tab) interceptedResponse(ctx *browserk.Context, message *gcdapi.FetchRe
 
 	modified := GCDFetchResponseToIntercepted(message, bodyStr, encoded)
 
-	ctx.NextResp(t, modified)
+	ctx.Copy().NextResp(t, modified)
 
 	if modified.Modified.ResponseCode != 0 {
 		respParams.ResponseCode = modified.Modified.ResponseCode
@@ -151,6 +151,26 @@ func KeyEncode(r rune) []*gcdapi.InputDispatchKeyEventParams {
 	return []*gcdapi.InputDispatchKeyEventParams{&keyDown, &keyUp}
 }
 
+// KeyEncodeModified encodes the keyDown, char, and keyUp sequence for the rune with the modifiers
+// held. No char event is sent when Alt, Ctrl or Meta are held as it's a shortcut, not text.
+func KeyEncodeModified(r rune, modifiers Modifier) []*gcdapi.InputDispatchKeyEventParams {
+	events := KeyEncode(r)
+	if modifiers == ModifierNone {
+		return events
+	}
+
+	shortcut := modifiers&(ModifierAlt|ModifierCtrl|ModifierMeta) != 0
+	encoded := make([]*gcdapi.InputDispatchKeyEventParams, 0, len(events))
+	for _, event := range events {
+		if shortcut && event.TheType == KeyChar.String() {
+			continue
+		}
+		event.Modifiers |= int(modifiers)
+		encoded = append(encoded, event)
+	}
+	return encoded
+}
+
 // DOM keys.
 const (
 	Backspace            = "\b"
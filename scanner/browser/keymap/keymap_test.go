@@ -0,0 +1,36 @@
+package keymap_test
+
+import (
+	"testing"
+
+	"gitlab.com/browserker/scanner/browser/keymap"
+)
+
+func TestKeyEncodeModified(t *testing.T) {
+	events := keymap.KeyEncodeModified('a', keymap.ModifierNone)
+	if len(events) != 3 || events[1].TheType != "char" {
+		t.Fatalf("expected keyDown, char, keyUp got %d events\n", len(events))
+	}
+
+	// shortcuts don't type text
+	events = keymap.KeyEncodeModified('a', keymap.ModifierCtrl)
+	if len(events) != 2 || events[0].TheType != "keyDown" || events[1].TheType != "keyUp" {
+		t.Fatalf("expected keyDown, keyUp got %d events\n", len(events))
+	}
+
+	for _, event := range events {
+		if event.Modifiers != int(keymap.ModifierCtrl) {
+			t.Fatalf("expected ctrl modifier got %d\n", event.Modifiers)
+		}
+	}
+
+	events = keymap.KeyEncodeModified('a', keymap.ModifierShift)
+	if len(events) != 3 || events[1].Modifiers&int(keymap.ModifierShift) == 0 {
+		t.Fatalf("expected shifted char got %d events\n", len(events))
+	}
+
+	events = keymap.KeyEncodeModified([]rune(keymap.ArrowDown)[0], keymap.ModifierNone)
+	if len(events) != 2 || events[0].Key != "ArrowDown" {
+		t.Fatalf("expected arrow down without char got %d events\n", len(events))
+	}
+}
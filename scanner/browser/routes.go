@@ -0,0 +1,47 @@
+package browser
+
+import (
+	"encoding/json"
+	"time"
+
+	"gitlab.com/browserker/browserk"
+)
+
+// urlBinding is called by the route script with each client side url change
+const urlBinding = "__browserkURLChange"
+
+// routeScript hooks the history api and listens for popstate/hashchange in the top level document
+// reporting the new location to our binding
+const routeScript = `(function() {
+	if (window !== window.top || window.__browserkRoutes) {
+		return;
+	}
+	window.__browserkRoutes = true;
+	const binding = window.` + urlBinding + `;
+	const report = function(type) {
+		try {
+			binding(JSON.stringify({type: type, url: window.location.href}));
+		} catch (e) {}
+	};
+
+	['pushState', 'replaceState'].forEach(function(method) {
+		const original = window.history[method];
+		window.history[method] = function() {
+			const ret = original.apply(this, arguments);
+			report(method);
+			return ret;
+		};
+	});
+	window.addEventListener('popstate', function() { report('popstate'); });
+	window.addEventListener('hashchange', function() { report('hashchange'); });
+})();`
+
+// ParseURLChange from the payload our route script sent to the binding
+func ParseURLChange(payload string) (*browserk.URLChangeEvent, error) {
+	evt := &browserk.URLChangeEvent{}
+	if err := json.Unmarshal([]byte(payload), evt); err != nil {
+		return nil, err
+	}
+	evt.Observed = time.Now()
+	return evt, nil
+}
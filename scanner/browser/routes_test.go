@@ -0,0 +1,22 @@
+package browser_test
+
+import (
+	"testing"
+
+	"gitlab.com/browserker/scanner/browser"
+)
+
+func TestParseURLChange(t *testing.T) {
+	evt, err := browser.ParseURLChange(`{"type":"pushState","url":"http://example.com/app/users"}`)
+	if err != nil {
+		t.Fatalf("error parsing url change: %s\n", err)
+	}
+
+	if evt.Type != "pushState" || evt.URL != "http://example.com/app/users" || evt.Observed.IsZero() {
+		t.Fatalf("unexpected url change %#v\n", evt)
+	}
+
+	if _, err := browser.ParseURLChange("not json"); err == nil {
+		t.Fatalf("expected error for invalid payload\n")
+	}
+}
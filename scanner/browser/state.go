@@ -0,0 +1,174 @@
+package browser
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gitlab.com/browserker/browserk"
+)
+
+const httpOnlyPrefix = "#HttpOnly_"
+
+// storageStateMarker is set in sessionStorage once the origin storage was applied to a tab
+// so we don't overwrite what the application changes on later navigations
+const storageStateMarker = "__browserk_state"
+
+// applyStorageJS sets the origin's storage on the first document loaded for that origin
+const applyStorageJS = `(function() {
+	if (location.origin !== %s) { return; }
+	try {
+		if (sessionStorage.getItem(%q)) { return; }
+		var local = %s;
+		var session = %s;
+		for (var k in local) { localStorage.setItem(k, local[k]); }
+		for (var k in session) { sessionStorage.setItem(k, session[k]); }
+		sessionStorage.setItem(%q, "1");
+	} catch (e) {}
+})();`
+
+// playwrightState is the storageState json written by playwright
+type playwrightState struct {
+	Cookies []struct {
+		Name     string  `json:"name"`
+		Value    string  `json:"value"`
+		Domain   string  `json:"domain"`
+		Path     string  `json:"path"`
+		Expires  float64 `json:"expires"`
+		HTTPOnly bool    `json:"httpOnly"`
+		Secure   bool    `json:"secure"`
+		SameSite string  `json:"sameSite"`
+	} `json:"cookies"`
+	Origins []struct {
+		Origin         string           `json:"origin"`
+		LocalStorage   []playwrightItem `json:"localStorage"`
+		SessionStorage []playwrightItem `json:"sessionStorage"`
+	} `json:"origins"`
+}
+
+type playwrightItem struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// LoadState from a file, json files are treated as playwright storageState
+// everything else as a netscape cookie jar
+func LoadState(fileName string) (*browserk.BrowserState, error) {
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		return ParseStorageState(data)
+	}
+	return ParseCookieJar(bytes.NewReader(data))
+}
+
+// ParseStorageState parses playwright's storageState json
+func ParseStorageState(data []byte) (*browserk.BrowserState, error) {
+	pw := &playwrightState{}
+	if err := json.Unmarshal(data, pw); err != nil {
+		return nil, errors.Wrap(err, "decoding storage state")
+	}
+
+	state := &browserk.BrowserState{
+		Cookies: make([]*browserk.Cookie, 0, len(pw.Cookies)),
+		Origins: make([]*browserk.OriginStorage, 0, len(pw.Origins)),
+	}
+
+	for _, c := range pw.Cookies {
+		state.Cookies = append(state.Cookies, &browserk.Cookie{
+			Name:     c.Name,
+			Value:    c.Value,
+			Domain:   c.Domain,
+			Path:     c.Path,
+			Expires:  c.Expires,
+			HTTPOnly: c.HTTPOnly,
+			Secure:   c.Secure,
+			Session:  c.Expires <= 0,
+			SameSite: c.SameSite,
+		})
+	}
+
+	for _, o := range pw.Origins {
+		origin := &browserk.OriginStorage{
+			Origin:         o.Origin,
+			LocalStorage:   make(map[string]string, len(o.LocalStorage)),
+			SessionStorage: make(map[string]string, len(o.SessionStorage)),
+		}
+		for _, item := range o.LocalStorage {
+			origin.LocalStorage[item.Name] = item.Value
+		}
+		for _, item := range o.SessionStorage {
+			origin.SessionStorage[item.Name] = item.Value
+		}
+		state.Origins = append(state.Origins, origin)
+	}
+	return state, nil
+}
+
+// ParseCookieJar parses the netscape cookie jar format written by curl, wget and browser extensions
+func ParseCookieJar(r io.Reader) (*browserk.BrowserState, error) {
+	state := &browserk.BrowserState{Cookies: make([]*browserk.Cookie, 0)}
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		httpOnly := strings.HasPrefix(line, httpOnlyPrefix)
+		if httpOnly {
+			line = strings.TrimPrefix(line, httpOnlyPrefix)
+		}
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+		if len(fields) != 7 {
+			return nil, fmt.Errorf("invalid cookie jar line %d: expected 7 fields got %d", lineNum, len(fields))
+		}
+
+		expires, err := strconv.ParseFloat(fields[4], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cookie jar line %d: bad expiry %s", lineNum, fields[4])
+		}
+
+		state.Cookies = append(state.Cookies, &browserk.Cookie{
+			Domain:   fields[0],
+			Path:     fields[2],
+			Secure:   strings.EqualFold(fields[3], "TRUE"),
+			Expires:  expires,
+			Session:  expires == 0,
+			Name:     fields[5],
+			Value:    fields[6],
+			HTTPOnly: httpOnly,
+		})
+	}
+	return state, scanner.Err()
+}
+
+// StorageScript returns the script that applies the origin's storage to new documents
+func StorageScript(origin *browserk.OriginStorage) (string, error) {
+	originJSON, err := json.Marshal(origin.Origin)
+	if err != nil {
+		return "", err
+	}
+	local, err := json.Marshal(origin.LocalStorage)
+	if err != nil {
+		return "", err
+	}
+	session, err := json.Marshal(origin.SessionStorage)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(applyStorageJS, originJSON, storageStateMarker, local, session, storageStateMarker), nil
+}
@@ -0,0 +1,123 @@
+package browser_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"gitlab.com/browserker/browserk"
+	"gitlab.com/browserker/mock"
+	"gitlab.com/browserker/scanner/browser"
+)
+
+func TestCookieJar(t *testing.T) {
+	state, err := browser.LoadState("testdata/state/cookies.txt")
+	if err != nil {
+		t.Fatalf("error loading cookie jar: %s\n", err)
+	}
+
+	if len(state.Cookies) != 2 {
+		t.Fatalf("expected 2 cookies got %d\n", len(state.Cookies))
+	}
+
+	session := state.Cookies[0]
+	if session.Name != "session" || session.Value != "abc123" || !session.Session || session.HTTPOnly {
+		t.Fatalf("session cookie was not parsed correctly: %#v\n", session)
+	}
+
+	token := state.Cookies[1]
+	if token.Domain != ".example.com" || token.Path != "/app" || !token.HTTPOnly || !token.Secure || token.Session {
+		t.Fatalf("httponly cookie was not parsed correctly: %#v\n", token)
+	}
+
+	if _, err := browser.ParseCookieJar(strings.NewReader("example.com\tFALSE\t/\n")); err == nil {
+		t.Fatalf("expected error for invalid line\n")
+	}
+}
+
+func TestStorageState(t *testing.T) {
+	state, err := browser.LoadState("testdata/state/storage_state.json")
+	if err != nil {
+		t.Fatalf("error loading storage state: %s\n", err)
+	}
+
+	if len(state.Cookies) != 2 {
+		t.Fatalf("expected 2 cookies got %d\n", len(state.Cookies))
+	}
+
+	if !state.Cookies[0].Session || !state.Cookies[0].HTTPOnly || state.Cookies[0].SameSite != "Lax" {
+		t.Fatalf("sid cookie was not parsed correctly: %#v\n", state.Cookies[0])
+	}
+
+	if len(state.Origins) != 1 {
+		t.Fatalf("expected 1 origin got %d\n", len(state.Origins))
+	}
+
+	origin := state.Origins[0]
+	if origin.LocalStorage["jwt"] != "eyJhbGciOi" || origin.SessionStorage["tab"] != "1" {
+		t.Fatalf("origin storage was not parsed correctly: %#v\n", origin)
+	}
+
+	script, err := browser.StorageScript(origin)
+	if err != nil {
+		t.Fatalf("error creating storage script: %s\n", err)
+	}
+
+	if !strings.Contains(script, `"http://localhost:8080"`) || !strings.Contains(script, `{"jwt":"eyJhbGciOi"}`) {
+		t.Fatalf("storage script missing state: %s\n", script)
+	}
+}
+
+func TestTakeState(t *testing.T) {
+	pool := browser.NewGCDBrowserPool(2, leaser)
+	if err := pool.Init(); err != nil {
+		t.Fatalf("failed to init pool")
+	}
+	defer leaser.Cleanup()
+
+	p, srv := testServer()
+	defer srv.Shutdown(context.Background())
+	target := fmt.Sprintf("http://localhost:%s/", p)
+
+	ctx := context.Background()
+	bCtx := mock.Context(ctx)
+	bCtx.State = &browserk.BrowserState{Cookies: []*browserk.Cookie{{Name: "session", Value: "abc123", Domain: "localhost", Path: "/", Session: true}}}
+
+	var tests = []struct {
+		name     string
+		ctx      *browserk.Context
+		expected bool
+	}{
+		{"crawl identity", bCtx.Copy(), true},
+		{"replay identity", mock.Context(ctx), false},
+	}
+
+	for _, tt := range tests {
+		b, port, err := pool.Take(tt.ctx)
+		if err != nil {
+			t.Fatalf("%s: error taking browser: %s\n", tt.name, err)
+		}
+
+		if err := b.Navigate(ctx, target); err != nil {
+			t.Fatalf("%s: error navigating: %s\n", tt.name, err)
+		}
+
+		cookies, err := b.GetCookies()
+		if err != nil {
+			t.Fatalf("%s: error getting cookies: %s\n", tt.name, err)
+		}
+
+		found := false
+		for _, cookie := range cookies {
+			if cookie.Name == "session" && cookie.Value == "abc123" {
+				found = true
+			}
+		}
+		if found != tt.expected {
+			t.Fatalf("%s: expected state applied %v got %v\n", tt.name, tt.expected, found)
+		}
+		b.Close()
+		pool.Return(ctx, port)
+	}
+}
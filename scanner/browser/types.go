@@ -1,6 +1,8 @@
 package browser
 
 import (
+	"context"
+
 	"github.com/pkg/errors"
 	"github.com/wirepair/gcd"
 	"github.com/wirepair/gcd/gcdapi"
@@ -46,6 +48,26 @@ var (
 	ErrBrowserClosing     = errors.New("unable to load, as closing down")
 )
 
+// FailureReason categorizes an error returned from a browser so we know why a navigation failed
+func FailureReason(err error) browserk.FailureReason {
+	var notFound *ErrElementNotFound
+	var invalid *ErrInvalidElement
+	var notReady *ErrElementNotReady
+	var invalidTab *ErrInvalidTab
+
+	switch {
+	case err == nil:
+		return browserk.FailUnknown
+	case errors.Is(err, ErrNavigationTimedOut), errors.Is(err, ErrTimedOut), errors.Is(err, context.DeadlineExceeded):
+		return browserk.FailTimeout
+	case errors.Is(err, ErrTabCrashed), errors.Is(err, ErrTabClosing), errors.Is(err, ErrBrowserClosing), errors.As(err, &invalidTab):
+		return browserk.FailBrowserCrash
+	case errors.As(err, &notFound), errors.As(err, &invalid), errors.As(err, &notReady):
+		return browserk.FailElementNotFound
+	}
+	return browserk.FailUnknown
+}
+
 // ErrElementNotFound when we are unable to find an element/nodeID
 type ErrElementNotFound struct {
 	Message string
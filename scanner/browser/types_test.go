@@ -0,0 +1,35 @@
+package browser_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	pkgerrors "github.com/pkg/errors"
+	"gitlab.com/browserker/browserk"
+	"gitlab.com/browserker/scanner/browser"
+)
+
+func TestFailureReason(t *testing.T) {
+	var tests = []struct {
+		err      error
+		expected browserk.FailureReason
+	}{
+		{browser.ErrNavigationTimedOut, browserk.FailTimeout},
+		{browser.ErrTimedOut, browserk.FailTimeout},
+		{context.DeadlineExceeded, browserk.FailTimeout},
+		{pkgerrors.Wrap(browser.ErrTabCrashed, "oom"), browserk.FailBrowserCrash},
+		{browser.ErrBrowserClosing, browserk.FailBrowserCrash},
+		{&browser.ErrInvalidTab{Message: "gone"}, browserk.FailBrowserCrash},
+		{&browser.ErrElementNotFound{}, browserk.FailElementNotFound},
+		{&browser.ErrInvalidElement{}, browserk.FailElementNotFound},
+		{errors.New("something else"), browserk.FailUnknown},
+		{nil, browserk.FailUnknown},
+	}
+
+	for _, tt := range tests {
+		if reason := browser.FailureReason(tt.err); reason != tt.expected {
+			t.Fatalf("%v: expected %s got %s\n", tt.err, browserk.FailureReasonMap[tt.expected], browserk.FailureReasonMap[reason])
+		}
+	}
+}
@@ -2,9 +2,11 @@ package scanner
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/url"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/rs/zerolog/log"
@@ -17,6 +19,28 @@ import (
 	"gitlab.com/browserker/scanner/report"
 )
 
+// maxRelogins is how many times a single path may login again before it is failed
+const maxRelogins = 1
+
+const (
+	defaultRetryBackoff = time.Second * 5
+	maxRetryBackoff     = time.Minute * 5
+	retryPollInterval   = time.Second * 5
+	defaultStaleTimeout = time.Minute * 10
+	// schedulePollInterval is how long the scheduler waits before checking for new work if no crawl completes
+	schedulePollInterval = time.Second * 5
+	// stopTimeout is how long Stop waits for active crawls before closing the stores
+	stopTimeout = time.Second * 30
+)
+
+// CrawlStats of a crawl
+type CrawlStats struct {
+	Crawled  int64 // paths crawled to completion
+	Failed   int64 // paths that still failed after being retried
+	Blocked  int64 // requests to excluded systems the browsers refused to send
+	Duration time.Duration
+}
+
 // Browserk is our engine
 type Browserk struct {
 	cfg          *browserk.Config
@@ -32,6 +56,17 @@ type Browserk struct {
 
 	idMutex          *sync.RWMutex
 	leasedBrowserIDs map[int64]struct{}
+	loginMutex       *sync.Mutex
+	blockedCount     int64 // requests to excluded systems the browsers refused to send
+	crawledCount     int64
+	failedCount      int64
+	activeCount      int64 // paths scheduled but not yet finished
+	activeMutex      *sync.Mutex
+	activeCrawls     map[string]*activeCrawl // crawls in progress by the id of the navigation they crawl to
+	crawlWait        sync.WaitGroup
+	started          time.Time
+	budget           *Budget
+	jsExtractor      *crawler.JSExtractor // shared so scripts are only analyzed once per crawl
 }
 
 // New engine
@@ -43,6 +78,9 @@ func New(cfg *browserk.Config, crawl browserk.CrawlGrapher, pluginStore browserk
 		reporter:         report.New(),
 		leasedBrowserIDs: make(map[int64]struct{}),
 		idMutex:          &sync.RWMutex{},
+		loginMutex:       &sync.Mutex{},
+		activeMutex:      &sync.Mutex{},
+		activeCrawls:     make(map[string]*activeCrawl),
 	}
 }
 
@@ -84,6 +122,7 @@ func (b *Browserk) Init(ctx context.Context) error {
 
 	b.mainContext = &browserk.Context{
 		Ctx:         cancelCtx,
+		Log:         &log.Logger,
 		CtxComplete: cancelFn,
 	}
 
@@ -92,9 +131,21 @@ func (b *Browserk) Init(ctx context.Context) error {
 		return err
 	}
 
-	b.mainContext.Auth = auth.New(b.cfg)
+	// the first identity crawls, the rest are used for access control checks
+	if len(b.cfg.Identities) > 0 {
+		b.cfg = identityConfig(b.cfg, b.cfg.Identities[0])
+	}
+
+	authService := auth.New(b.cfg)
+	if err := authService.Init(); err != nil {
+		return err
+	}
 	b.mainContext.Scope = b.scopeService(target)
-	b.mainContext.FormHandler = crawler.NewCrawlerFormHandler(b.cfg.FormData)
+	b.mainContext.Auth = authService
+	authService.AddHandlers(b.mainContext)
+	formHandler := crawler.NewCrawlerFormHandler(b.cfg.FormData)
+	formHandler.SetCredentials(b.cfg.Credentials)
+	b.mainContext.FormHandler = formHandler
 	b.mainContext.Reporter = b.reporter
 	b.mainContext.Injector = nil
 	b.mainContext.Crawl = b.crawlGraph
@@ -102,7 +153,7 @@ func (b *Browserk) Init(ctx context.Context) error {
 
 	log.Info().Int("num_browsers", b.cfg.NumBrowsers).Int("max_depth", b.cfg.MaxDepth).Msg("Initializing...")
 	b.navCh = make(chan []*browserk.Navigation, b.cfg.NumBrowsers)
-	b.readyCh = make(chan struct{})
+	b.readyCh = make(chan struct{}, 1)
 
 	log.Logger.Info().Msg("initializing attack graph")
 	if err := b.pluginStore.Init(); err != nil {
@@ -110,11 +161,14 @@ func (b *Browserk) Init(ctx context.Context) error {
 	}
 
 	log.Logger.Info().Msg("initializing crawl graph")
+	b.budget = NewBudget(b.cfg)
+	b.jsExtractor = crawler.NewJSExtractor()
+	b.crawlGraph.SetMaxDepth(b.cfg.MaxDepth)
 	if err := b.crawlGraph.Init(); err != nil {
 		return err
 	}
 
-	b.formHandler = crawler.NewCrawlerFormHandler(b.cfg.FormData)
+	b.formHandler = formHandler
 
 	b.initNavigation()
 
@@ -125,9 +179,61 @@ func (b *Browserk) Init(ctx context.Context) error {
 	log.Logger.Info().Msg("leaser started")
 	pool := browser.NewGCDBrowserPool(b.cfg.NumBrowsers, leaser)
 	b.browsers = pool
+	if b.cfg.StateFile != "" {
+		state, err := browser.LoadState(b.cfg.StateFile)
+		if err != nil {
+			return fmt.Errorf("loading browser state: %w", err)
+		}
+		log.Logger.Info().Int("cookies", len(state.Cookies)).Int("origins", len(state.Origins)).Msg("applying browser state")
+		// only the crawling identity uses the state, replayed identities start without it
+		b.mainContext.State = state
+	}
 	log.Logger.Info().Msg("starting browser pool")
+	if err := pool.Init(); err != nil {
+		return err
+	}
+
+	if b.mainContext.Auth.MustLogin() {
+		if err := b.login(b.mainContext); err != nil {
+			return err
+		}
+	}
 	go b.processEntries()
-	return pool.Init()
+	return nil
+}
+
+// login once with a dedicated browser so the session can be restored in every crawl browser
+func (b *Browserk) login(c *browserk.Context) error {
+	loginCtx := c.Copy()
+	browser, port, err := b.browsers.Take(loginCtx)
+	if err != nil {
+		return err
+	}
+	defer b.browsers.Return(loginCtx.Ctx, port)
+	defer browser.Close()
+
+	log.Info().Msg("logging in")
+	if err := loginCtx.Auth.Login(loginCtx, browser); err != nil {
+		log.Error().Err(err).Msg("failed to login")
+		return err
+	}
+	log.Info().Int("cookies", len(loginCtx.Auth.Session().Cookies)).Msg("captured login session")
+	return nil
+}
+
+// relogin after a crawl browser lost its session. If another browser already logged in
+// again after we noticed, we just restore that session instead.
+func (b *Browserk) relogin(browser browserk.Browser, detected time.Time) error {
+	b.loginMutex.Lock()
+	defer b.loginMutex.Unlock()
+
+	loginCtx := b.mainContext.Copy()
+	if session := loginCtx.Auth.Session(); session != nil && session.Observed.After(detected) {
+		return loginCtx.Auth.RestoreSession(loginCtx, browser)
+	}
+
+	log.Info().Int64("browser_id", browser.ID()).Msg("session lost, logging in again")
+	return loginCtx.Auth.Login(loginCtx, browser)
 }
 
 func (b *Browserk) initNavigation() {
@@ -141,7 +247,9 @@ func (b *Browserk) initNavigation() {
 	nav.Distance = 0
 
 	// reset any inprocess navigations to unvisited because it didn't exit cleanly
-	b.crawlGraph.Find(b.mainContext.Ctx, browserk.NavInProcess, browserk.NavUnvisited, 1000)
+	if reset := b.crawlGraph.ResetInProcess(); reset > 0 {
+		log.Info().Int("navigations", reset).Msg("resuming crawl, reset in process navigations to unvisited")
+	}
 
 	if !b.crawlGraph.NavExists(nav) {
 		b.crawlGraph.AddNavigation(nav)
@@ -149,6 +257,40 @@ func (b *Browserk) initNavigation() {
 	} else {
 		log.Info().Msg("Navigation for Load URL already exists")
 	}
+
+	if !b.cfg.DisableSeeding {
+		b.seedNavigation()
+	}
+}
+
+// seedNavigation adds the in scope urls of robots.txt, sitemap.xml and security.txt
+// so we reach pages that are never linked to
+func (b *Browserk) seedNavigation() {
+	target, err := url.Parse(b.cfg.URL)
+	if err != nil {
+		return
+	}
+
+	urls := crawler.NewSeeder(b.mainContext.Scope).Seed(b.mainContext.Ctx, target)
+	seeded := 0
+	for _, seedURL := range urls {
+		nav := browserk.NewNavigation(browserk.TrigInitial, &browserk.Action{
+			Type:  browserk.ActLoadURL,
+			Input: []byte(seedURL),
+		})
+		nav.Scope = browserk.InScope
+		nav.Distance = 0
+		if b.crawlGraph.NavExists(nav) {
+			continue
+		}
+
+		if err := b.crawlGraph.AddNavigation(nav); err != nil {
+			log.Error().Err(err).Str("url", seedURL).Msg("failed to add seed navigation")
+			continue
+		}
+		seeded++
+	}
+	log.Info().Int("seeded", seeded).Msg("seeded crawl graph from robots.txt, sitemap.xml and security.txt")
 }
 
 func (b *Browserk) scopeService(target *url.URL) browserk.ScopeService {
@@ -163,38 +305,137 @@ func (b *Browserk) scopeService(target *url.URL) browserk.ScopeService {
 	if b.cfg.ExcludedURIs != nil {
 		scope.AddExcludedURIs(b.cfg.ExcludedURIs)
 	}
+	scope.ExcludeForms(b.cfg.ExcludedForms)
 	return scope
 }
 
-// Start the browsers
+// Start crawling, keeping every browser busy until there are no more unvisited navigations,
+// no navigations waiting to be retried and no crawls in progress, the budget is exhausted or
+// the crawl is cancelled
 func (b *Browserk) Start() error {
+	b.started = time.Now()
+	defer b.logStats()
+
+	exhausted := false
 	for {
+		if b.mainContext.Ctx.Err() != nil {
+			log.Info().Msg("crawl cancelled")
+			return nil
+		}
+
+		active := int(atomic.LoadInt64(&b.activeCount))
+		found, waiting := 0, 0
+		if reason, ok := b.budget.Exhausted(); ok && !exhausted {
+			log.Info().Str("reason", reason).Int("active", active).Msg("crawl budget exhausted, waiting for active crawls")
+			exhausted = true
+		}
+
+		if free := b.cfg.NumBrowsers - active; free > 0 && !exhausted {
+			found, waiting = b.schedule(free)
+		}
 
-		log.Info().Msg("searching for new navigation entries")
-		entries := b.crawlGraph.Find(b.mainContext.Ctx, browserk.NavUnvisited, browserk.NavInProcess, int64(b.cfg.NumBrowsers))
-		if entries == nil || len(entries) == 0 && b.browsers.Leased() == 0 {
+		if found == 0 && waiting == 0 && active == 0 && b.browsers.Leased() == 0 {
 			log.Info().Msg("no more crawler entries or active browsers")
-			time.Sleep(time.Second * 60)
+			b.checkAccessControl()
 			return nil
 		}
-		log.Info().Int("entries", len(entries)).Msg("Found entries")
-		for _, nav := range entries {
-			b.navCh <- nav
+
+		select {
+		case <-b.readyCh:
+		case <-time.After(schedulePollInterval):
+		case <-b.mainContext.Ctx.Done():
 		}
-		log.Info().Msg("Waiting for crawler to complete")
-		<-b.readyCh
 	}
 }
 
+// schedule up to free paths to be crawled, returning how many were found and how many failed
+// navigations are waiting to be retried
+func (b *Browserk) schedule(free int) (int, int) {
+	limit := free
+	if remaining := b.budget.Remaining(); remaining > 0 && remaining < limit {
+		limit = remaining
+	}
+
+	retried, waiting := b.crawlGraph.RetryNavigations(b.retryBackoff)
+	if retried > 0 {
+		log.Info().Int("retried", retried).Msg("retrying failed navigations")
+	}
+
+	entries := b.crawlGraph.Find(b.mainContext.Ctx, browserk.NavUnvisited, browserk.NavInProcess, int64(limit))
+	if len(entries) == 0 {
+		return 0, waiting
+	}
+
+	log.Info().Int("entries", len(entries)).Int("free_browsers", free).Msg("scheduling entries")
+	navIDs := make([][]byte, 0, len(entries))
+	for _, nav := range entries {
+		navIDs = append(navIDs, nav[len(nav)-1].ID)
+	}
+	b.budget.Take(navIDs)
+	for _, nav := range entries {
+		atomic.AddInt64(&b.activeCount, 1)
+		b.crawlWait.Add(1)
+		b.navCh <- nav
+	}
+	return len(entries), waiting
+}
+
+// crawlDone marks a crawl as complete and wakes the scheduler
+func (b *Browserk) crawlDone() {
+	atomic.AddInt64(&b.activeCount, -1)
+	b.crawlWait.Done()
+	b.wake()
+}
+
+// wake the scheduler if it's waiting
+func (b *Browserk) wake() {
+	select {
+	case b.readyCh <- struct{}{}:
+	default:
+	}
+}
+
+// Stats of the crawl so far
+func (b *Browserk) Stats() *CrawlStats {
+	stats := &CrawlStats{
+		Crawled: atomic.LoadInt64(&b.crawledCount),
+		Failed:  atomic.LoadInt64(&b.failedCount),
+		Blocked: atomic.LoadInt64(&b.blockedCount),
+	}
+
+	if !b.started.IsZero() {
+		stats.Duration = time.Since(b.started)
+	}
+	return stats
+}
+
+func (b *Browserk) logStats() {
+	stats := b.Stats()
+	log.Info().
+		Int64("crawled", stats.Crawled).
+		Int64("failed", stats.Failed).
+		Int64("blocked_requests", stats.Blocked).
+		Dur("duration", stats.Duration).
+		Msg("crawl complete")
+}
+
 func (b *Browserk) processEntries() {
 	for {
 		select {
 		case <-b.stateMonitor.C:
-			// TODO: check graph for inprocess values that never made it and reset them to unvisited
 			log.Info().Int("leased_browsers", b.browsers.Leased()).Ints64("leased_browsers", b.getLeased()).Msg("state monitor ping")
+			b.resetStale()
 		case <-b.mainContext.Ctx.Done():
 			log.Info().Msg("scan finished due to context complete")
-			return
+			// paths that were scheduled but never started
+			for {
+				select {
+				case <-b.navCh:
+					b.crawlDone()
+				default:
+					return
+				}
+			}
 		case nav := <-b.navCh:
 			log.Info().Int("leased_browsers", b.browsers.Leased()).Msg("processing nav")
 			go b.crawl(nav)
@@ -205,32 +446,57 @@ func (b *Browserk) processEntries() {
 }
 
 func (b *Browserk) crawl(navs []*browserk.Navigation) {
+	// failures are recorded against the navigation we are trying to reach
+	target := navs[len(navs)-1]
+	active := b.startCrawl(target.ID)
+	defer b.releaseCrawl(active)
+
 	navCtx := b.mainContext.Copy()
+	navCtx.Ctx = active.ctx
 
 	browser, port, err := b.browsers.Take(navCtx)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to take browser")
+		if !active.isStale() {
+			b.failNavigation(target, browserk.FailBrowserCrash, err)
+		}
 		return
 	}
 
 	b.addLeased(browser.ID())
-	defer b.removeLeased(browser.ID())
+	if !active.setBrowser(browser, port) {
+		// went stale while we were waiting for a browser
+		log.Warn().Str("nav_id", string(target.ID)).Msg("navigation went stale before crawling")
+		b.removeLeased(browser.ID())
+		browser.Close()
+		b.browsers.Return(b.mainContext.Ctx, port)
+		return
+	}
 
-	crawler := crawler.New(b.cfg)
+	if navCtx.Auth.MustLogin() {
+		if err := navCtx.Auth.RestoreSession(navCtx, browser); err != nil {
+			log.Error().Err(err).Msg("failed to restore login session")
+		}
+	}
+
+	crawler := crawler.New(b.cfg).SetJSExtractor(b.jsExtractor)
 	if err := crawler.Init(); err != nil {
-		b.browsers.Return(navCtx.Ctx, port)
 		log.Error().Err(err).Msg("failed to init crawler")
+		b.failNavigation(target, browserk.FailUnknown, err)
 		return
 	}
 
-	isFinal := false
-	for i, nav := range navs {
+	relogins := 0
+	failed := false
+	// results are only recorded once the path is done so a replay after logging in again does
+	// not record the steps twice
+	results := make([]*browserk.NavigationResult, 0, len(navs))
+	for i := 0; i < len(navs); i++ {
+		nav := navs[i]
 		// we are on the last navigation of this path so we'll want to capture some stuff
-		if i == len(navs)-1 {
-			isFinal = true
-		}
+		isFinal := i == len(navs)-1
 
-		ctx, cancel := context.WithTimeout(navCtx.Ctx, time.Second*45)
+		ctx, cancel := context.WithTimeout(active.ctx, time.Second*45)
 		navCtx.Ctx = ctx
 		logger := log.With().
 			Int64("browser_id", browser.ID()).
@@ -238,29 +504,92 @@ func (b *Browserk) crawl(navs []*browserk.Navigation) {
 			Logger()
 		navCtx.Log = &logger
 
-		defer cancel()
-
 		result, newNavs, err := crawler.Process(navCtx, browser, nav, isFinal)
+		if active.isStale() {
+			cancel()
+			navCtx.Log.Warn().Msg("navigation went stale, discarding crawl")
+			return
+		}
+
 		if err != nil {
+			cancel()
 			navCtx.Log.Error().Err(err).Msg("failed to process action")
-			b.crawlGraph.FailNavigation(nav.ID)
+			b.failNavigation(target, browserk.FailUnknown, err)
+			failed = true
 			break
 		}
+		b.logBlocked(navCtx, result)
+
+		if navCtx.Auth.MustLogin() && navCtx.Auth.IsLoggedOut(navCtx, browser, nav, result) {
+			if relogins >= maxRelogins {
+				cancel()
+				navCtx.Log.Error().Msg("still logged out after logging in again")
+				b.failNavigation(target, browserk.FailLoggedOut, errors.New("still logged out after logging in again"))
+				failed = true
+				break
+			}
+			relogins++
+
+			if err := b.relogin(browser, time.Now()); err != nil {
+				cancel()
+				navCtx.Log.Error().Err(err).Msg("failed to login again")
+				b.failNavigation(target, browserk.FailLoggedOut, err)
+				failed = true
+				break
+			}
+			// replay the path from the start with the new session
+			cancel()
+			results = results[:0]
+			i = -1
+			continue
+		}
 
 		if isFinal {
+			b.applyPatternBudget(navCtx, result.EndURL, newNavs)
 			navCtx.Log.Info().Int("nav_count", len(newNavs)).Bool("is_final", isFinal).Msg("adding new navs")
 			if err := b.crawlGraph.AddNavigations(newNavs); err != nil {
 				navCtx.Log.Error().Err(err).Msg("failed to add new navigations")
 			}
 		}
+		results = append(results, result)
+		cancel()
+	}
+
+	if active.isStale() {
+		navCtx.Log.Warn().Msg("navigation went stale, discarding crawl")
+		return
+	}
+	for _, result := range results {
 		if err := b.crawlGraph.AddResult(result); err != nil {
 			navCtx.Log.Error().Err(err).Msg("failed to add result")
 		}
 	}
+	if !failed {
+		atomic.AddInt64(&b.crawledCount, 1)
+	}
 	navCtx.Log.Info().Msg("closing browser")
-	browser.Close()
-	b.browsers.Return(navCtx.Ctx, port)
-	b.readyCh <- struct{}{}
+}
+
+// Cancel the crawl, Start returns once the scheduler notices
+func (b *Browserk) Cancel() {
+	if b.mainContext != nil {
+		b.mainContext.CtxComplete()
+	}
+}
+
+// waitForCrawls to finish so they don't write to closed stores
+func (b *Browserk) waitForCrawls(timeout time.Duration) {
+	done := make(chan struct{})
+	go func() {
+		b.crawlWait.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		log.Warn().Int64("active", atomic.LoadInt64(&b.activeCount)).Msg("timed out waiting for active crawls")
+	}
 }
 
 // Stop the browsers
@@ -268,6 +597,7 @@ func (b *Browserk) Stop() error {
 
 	log.Info().Msg("Completing Ctx")
 	b.mainContext.CtxComplete()
+	log.Info().Int64("blocked_requests", atomic.LoadInt64(&b.blockedCount)).Msg("requests to excluded systems blocked")
 
 	log.Info().Msg("Stopping browsers")
 	err := b.browsers.Shutdown()
@@ -275,6 +605,9 @@ func (b *Browserk) Stop() error {
 		log.Warn().Err(err).Msg("failed to close browsers")
 	}
 
+	log.Info().Msg("Waiting for active crawls")
+	b.waitForCrawls(stopTimeout)
+
 	log.Info().Msg("Closing plugin store")
 	err = b.pluginStore.Close()
 	if err != nil {
@@ -289,6 +622,99 @@ func (b *Browserk) Stop() error {
 	return err
 }
 
+// resetStale returns in process navigations that never finished (browser died or wedged mid path)
+// to unvisited and wakes the scheduler so it does not wait on them any longer
+func (b *Browserk) resetStale() {
+	timeout := time.Duration(b.cfg.StaleTimeout) * time.Second
+	if timeout <= 0 {
+		timeout = defaultStaleTimeout
+	}
+
+	reset := b.crawlGraph.ResetStaleNavigations(timeout)
+	if len(reset) == 0 {
+		return
+	}
+	log.Warn().Int("navigations", len(reset)).Dur("timeout", timeout).Msg("reset stale in process navigations to unvisited")
+
+	// free the browsers and slots of the crawls that were stuck on them
+	b.cancelStale(reset)
+	b.wake()
+}
+
+// failNavigation records why the navigation failed, it is retried after a backoff until
+// it has failed more than MaxRetries times. Unknown reasons are categorized from the error
+func (b *Browserk) failNavigation(nav *browserk.Navigation, reason browserk.FailureReason, navErr error) {
+	// cancelled crawls leave the navigation in process so it's reset when resuming
+	if b.mainContext.Ctx.Err() != nil {
+		return
+	}
+
+	if reason == browserk.FailUnknown {
+		reason = browser.FailureReason(navErr)
+	}
+
+	state, err := b.crawlGraph.FailNavigation(nav.ID, reason, navErr, b.cfg.MaxRetries)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to record navigation failure")
+		return
+	}
+	// only count navigations once we've given up on them, not every failed attempt
+	if state == browserk.NavFailed {
+		atomic.AddInt64(&b.failedCount, 1)
+	}
+	log.Info().Str("reason", browserk.FailureReasonMap[reason]).Bool("retry", state == browserk.NavRetry).Msg("navigation failed")
+}
+
+// retryBackoff doubles the RetryBackoff for each failure
+func (b *Browserk) retryBackoff(failures int) time.Duration {
+	backoff := time.Duration(b.cfg.RetryBackoff) * time.Second
+	if backoff <= 0 {
+		backoff = defaultRetryBackoff
+	}
+
+	for i := 1; i < failures && backoff < maxRetryBackoff; i++ {
+		backoff *= 2
+	}
+
+	if backoff > maxRetryBackoff {
+		return maxRetryBackoff
+	}
+	return backoff
+}
+
+// applyPatternBudget skips the new navigations whose url path pattern has been crawled enough
+func (b *Browserk) applyPatternBudget(navCtx *browserk.Context, pageURL string, navs []*browserk.Navigation) {
+	skipped := 0
+	for _, nav := range navs {
+		if nav.State != browserk.NavUnvisited {
+			continue
+		}
+
+		if !b.budget.AllowNavigation(pageURL, nav) {
+			nav.State = browserk.NavSkipped
+			skipped++
+		}
+	}
+
+	if skipped > 0 {
+		navCtx.Log.Info().Int("skipped", skipped).Msg("skipped navigations exceeding the path pattern budget")
+	}
+}
+
+// logBlocked requests of the navigation and add them to the total
+func (b *Browserk) logBlocked(navCtx *browserk.Context, result *browserk.NavigationResult) {
+	if len(result.BlockedRequests) == 0 {
+		return
+	}
+	atomic.AddInt64(&b.blockedCount, int64(len(result.BlockedRequests)))
+
+	urls := make([]string, 0, len(result.BlockedRequests))
+	for _, blocked := range result.BlockedRequests {
+		urls = append(urls, blocked.Method+" "+blocked.URL)
+	}
+	navCtx.Log.Info().Int("blocked_count", len(urls)).Strs("blocked", urls).Msg("blocked requests to excluded systems")
+}
+
 func (b *Browserk) printActionStep(navs []*browserk.Navigation) string {
 	pathString := ""
 	for i, path := range navs {
@@ -0,0 +1,170 @@
+package scanner
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"gitlab.com/browserker/browserk"
+)
+
+// idSegmentRe matches path segments that are most likely identifiers (numbers, uuids, hashes)
+var idSegmentRe = regexp.MustCompile(`^(\d+|[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}|[0-9a-fA-F]{16,})$`)
+
+// Budget limits how much we crawl so large sites finish in a reasonable time
+type Budget struct {
+	maxNavs       int
+	maxPerPattern int
+	deadline      time.Time
+
+	lock     sync.Mutex
+	navs     int
+	patterns map[string]int
+	counted  map[string]struct{} // navigation ids already counted towards a pattern
+	charged  map[string]struct{} // navigation ids already counted towards maxNavs
+}
+
+// NewBudget from the config, a 0 for any of the limits disables it
+func NewBudget(cfg *browserk.Config) *Budget {
+	b := &Budget{
+		maxNavs:       cfg.MaxNavigations,
+		maxPerPattern: cfg.MaxPerPathPattern,
+		patterns:      make(map[string]int),
+		counted:       make(map[string]struct{}),
+		charged:       make(map[string]struct{}),
+	}
+
+	if cfg.MaxDuration > 0 {
+		b.deadline = time.Now().Add(time.Duration(cfg.MaxDuration) * time.Second)
+	}
+	return b
+}
+
+// Remaining navigations we may crawl, -1 if there is no limit
+func (b *Budget) Remaining() int {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.maxNavs <= 0 {
+		return -1
+	}
+
+	if b.navs >= b.maxNavs {
+		return 0
+	}
+	return b.maxNavs - b.navs
+}
+
+// Take the navigations from the budget, navigations that were already taken (retries of
+// failed or stale navigations) are not counted again
+func (b *Budget) Take(navIDs [][]byte) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	for _, navID := range navIDs {
+		if _, ok := b.charged[string(navID)]; ok {
+			continue
+		}
+		b.charged[string(navID)] = struct{}{}
+		b.navs++
+	}
+}
+
+// Exhausted returns the reason if we've reached the navigation or time limit
+func (b *Budget) Exhausted() (string, bool) {
+	if b.Remaining() == 0 {
+		return "max navigations reached", true
+	}
+
+	if !b.deadline.IsZero() && time.Now().After(b.deadline) {
+		return "max duration reached", true
+	}
+	return "", false
+}
+
+// AllowPattern returns true and counts the navigation if the path pattern of its url has not
+// reached the limit, navigations we've already allowed are not counted again
+func (b *Budget) AllowPattern(navID []byte, uri string) bool {
+	if b.maxPerPattern <= 0 {
+		return true
+	}
+
+	pattern := PathPattern(uri)
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if _, ok := b.counted[string(navID)]; ok {
+		return true
+	}
+
+	if b.patterns[pattern] >= b.maxPerPattern {
+		return false
+	}
+	b.patterns[pattern]++
+	b.counted[string(navID)] = struct{}{}
+	return true
+}
+
+// AllowNavigation returns true if the navigation found on pageURL is within the path pattern budget.
+// Only navigations that go to another url are counted, actions that stay on the page (buttons,
+// forms posting back to the page) don't use up the page's pattern
+func (b *Budget) AllowNavigation(pageURL string, nav *browserk.Navigation) bool {
+	target, ok := navigationURL(pageURL, nav)
+	if !ok {
+		return true
+	}
+	return b.AllowPattern(nav.ID, target)
+}
+
+// PathPattern of a url, replacing identifier like path segments with {id} so
+// /products/1 and /products/2 count towards the same pattern
+func PathPattern(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return uri
+	}
+
+	segments := strings.Split(u.Path, "/")
+	for i, segment := range segments {
+		if idSegmentRe.MatchString(segment) {
+			segments[i] = "{id}"
+		}
+	}
+	return strings.ToLower(u.Host + strings.Join(segments, "/"))
+}
+
+// navigationURL returns the url the navigation goes to and true if it's a different
+// url than the page it was found on
+func navigationURL(pageURL string, nav *browserk.Navigation) (string, bool) {
+	target := ""
+	switch {
+	case nav.Action.Type == browserk.ActLoadURL:
+		return string(nav.Action.Input), true
+	case nav.Action.Form != nil:
+		target = nav.Action.Form.GetAttribute("action")
+	case nav.Action.Element != nil && nav.Action.Element.Type == browserk.A:
+		target = nav.Action.Element.GetAttribute("href")
+	default:
+		return "", false
+	}
+
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return "", false
+	}
+
+	ref, err := url.Parse(target)
+	if err != nil || strings.HasPrefix(strings.ToLower(target), "javascript:") {
+		return "", false
+	}
+
+	resolved := base.ResolveReference(ref)
+	resolved.Fragment = ""
+	base.Fragment = ""
+	if resolved.String() == base.String() {
+		return "", false
+	}
+	return resolved.String(), true
+}
@@ -0,0 +1,114 @@
+package scanner_test
+
+import (
+	"fmt"
+	"testing"
+
+	"gitlab.com/browserker/browserk"
+	"gitlab.com/browserker/scanner"
+)
+
+func TestPathPattern(t *testing.T) {
+	var tests = []struct {
+		in       string
+		expected string
+	}{
+		{"http://example.com/products/1", "example.com/products/{id}"},
+		{"http://example.com/products/2?sort=asc", "example.com/products/{id}"},
+		{"http://example.com/users/3f2504e0-4f89-11d3-9a0c-0305e82c3301/edit", "example.com/users/{id}/edit"},
+		{"http://example.com/commit/9fceb02d0ae598e95dc970b74767f19372d61af8", "example.com/commit/{id}"},
+		{"http://example.com/products/shoes", "example.com/products/shoes"},
+		{"http://example.com/v2/api", "example.com/v2/api"},
+	}
+
+	for _, tt := range tests {
+		if pattern := scanner.PathPattern(tt.in); pattern != tt.expected {
+			t.Fatalf("expected %s got %s for %s\n", tt.expected, pattern, tt.in)
+		}
+	}
+}
+
+func TestBudget(t *testing.T) {
+	b := scanner.NewBudget(&browserk.Config{MaxNavigations: 3, MaxPerPathPattern: 2})
+	if b.Remaining() != 3 {
+		t.Fatalf("expected 3 remaining got %d\n", b.Remaining())
+	}
+
+	if !b.AllowPattern([]byte{1}, "http://example.com/products/1") || !b.AllowPattern([]byte{2}, "http://example.com/products/2") {
+		t.Fatalf("expected first two products to be allowed\n")
+	}
+
+	if b.AllowPattern([]byte{3}, "http://example.com/products/3") {
+		t.Fatalf("expected third product to exceed the pattern budget\n")
+	}
+
+	if !b.AllowPattern([]byte{1}, "http://example.com/products/1") {
+		t.Fatalf("expected an already allowed navigation to be allowed again\n")
+	}
+
+	if !b.AllowPattern([]byte{4}, "http://example.com/about") {
+		t.Fatalf("expected other patterns to be allowed\n")
+	}
+
+	b.Take([][]byte{{1}, {2}})
+	if _, exhausted := b.Exhausted(); exhausted {
+		t.Fatalf("budget should not be exhausted yet\n")
+	}
+
+	// retried navigations were already paid for
+	b.Take([][]byte{{1}, {2}})
+	if _, exhausted := b.Exhausted(); exhausted {
+		t.Fatalf("retried navigations should not be counted again\n")
+	}
+
+	b.Take([][]byte{{3}})
+	if reason, exhausted := b.Exhausted(); !exhausted || reason == "" {
+		t.Fatalf("budget should be exhausted\n")
+	}
+
+	unlimited := scanner.NewBudget(&browserk.Config{})
+	for i := 0; i < 1000; i++ {
+		unlimited.Take([][]byte{[]byte(fmt.Sprintf("%d", i))})
+	}
+	if _, exhausted := unlimited.Exhausted(); exhausted || unlimited.Remaining() != -1 {
+		t.Fatalf("expected unlimited budget\n")
+	}
+
+	expired := scanner.NewBudget(&browserk.Config{MaxDuration: -1})
+	if _, exhausted := expired.Exhausted(); exhausted {
+		t.Fatalf("negative duration should not limit\n")
+	}
+}
+
+func TestBudgetNavigations(t *testing.T) {
+	b := scanner.NewBudget(&browserk.Config{MaxPerPathPattern: 2})
+	page := "http://example.com/products/1"
+	from := browserk.NewNavigation(browserk.TrigInitial, browserk.NewLoadURLAction(page))
+
+	// a page full of buttons and forms posting back to it doesn't use up its pattern
+	for i := 0; i < 10; i++ {
+		button := &browserk.HTMLElement{Type: browserk.BUTTON, Attributes: map[string]string{"id": fmt.Sprintf("add%d", i)}}
+		if !b.AllowNavigation(page, browserk.NewNavigationFromElement(from, browserk.TrigCrawler, button, browserk.ActLeftClick)) {
+			t.Fatalf("expected button %d to be allowed\n", i)
+		}
+
+		form := &browserk.HTMLFormElement{Attributes: map[string]string{"id": fmt.Sprintf("review%d", i)}}
+		if !b.AllowNavigation(page, browserk.NewNavigationFromForm(from, browserk.TrigCrawler, form)) {
+			t.Fatalf("expected form %d to be allowed\n", i)
+		}
+
+		anchor := &browserk.HTMLElement{Type: browserk.A, Attributes: map[string]string{"href": fmt.Sprintf("#tab%d", i)}}
+		if !b.AllowNavigation(page, browserk.NewNavigationFromElement(from, browserk.TrigCrawler, anchor, browserk.ActLeftClick)) {
+			t.Fatalf("expected in page link %d to be allowed\n", i)
+		}
+	}
+
+	// links to similar urls still get the whole budget
+	for i := 2; i < 5; i++ {
+		link := &browserk.HTMLElement{Type: browserk.A, Attributes: map[string]string{"href": fmt.Sprintf("/products/%d", i)}}
+		allowed := b.AllowNavigation(page, browserk.NewNavigationFromElement(from, browserk.TrigCrawler, link, browserk.ActLeftClick))
+		if allowed != (i < 4) {
+			t.Fatalf("expected /products/%d allowed to be %v\n", i, i < 4)
+		}
+	}
+}
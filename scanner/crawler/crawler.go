@@ -2,20 +2,42 @@ package crawler
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/rs/zerolog/log"
 	"gitlab.com/browserker/browserk"
 )
 
+const (
+	defaultMaxScrolls = 10
+	scrollWait        = time.Millisecond * 300
+)
+
+// scrollableJS returns true if the document is taller than the viewport
+const scrollableJS = `(function() {
+	var doc = document.scrollingElement || document.documentElement;
+	return doc.scrollHeight > window.innerHeight;
+})()`
+
 // BrowserkCrawler crawls a site
 type BrowserkCrawler struct {
 	cfg *browserk.Config
+	js  *JSExtractor
 }
 
 // New crawler for a site
 func New(cfg *browserk.Config) *BrowserkCrawler {
-	return &BrowserkCrawler{cfg: cfg}
+	return &BrowserkCrawler{cfg: cfg, js: NewJSExtractor()}
+}
+
+// SetJSExtractor shares the extractor between crawlers so scripts are only analyzed once
+func (b *BrowserkCrawler) SetJSExtractor(js *JSExtractor) *BrowserkCrawler {
+	b.js = js
+	return b
 }
 
 // Init the crawler, if necessary
@@ -37,6 +59,9 @@ func (b *BrowserkCrawler) Process(bctx *browserk.Context, browser browserk.Brows
 	//clear out storage and console events before executing our action
 	browser.GetStorageEvents()
 	browser.GetConsoleEvents()
+	browser.GetBlockedRequests()
+	browser.GetAuthChallenges()
+	browser.GetURLChanges()
 
 	if isFinal {
 		diff = b.snapshot(bctx, browser)
@@ -67,6 +92,10 @@ func (b *BrowserkCrawler) Process(bctx *browserk.Context, browser browserk.Brows
 	potentialNavs := make([]*browserk.Navigation, 0)
 	if isFinal {
 		potentialNavs = b.FindNewNav(bctx, diff, entry, browser)
+		potentialNavs = append(potentialNavs, b.TypeAheadNavs(bctx, diff, entry, browser)...)
+		potentialNavs = append(potentialNavs, b.ScrollNavs(bctx, entry, browser)...)
+		potentialNavs = append(potentialNavs, b.RouteNavs(bctx, entry, result)...)
+		potentialNavs = append(potentialNavs, b.ScriptNavs(bctx, entry, result.EndURL, browser.GetBaseHref(), browser.GetScripts())...)
 	}
 	return result, potentialNavs, nil
 }
@@ -88,6 +117,9 @@ func (b *BrowserkCrawler) buildResult(result *browserk.NavigationResult, start t
 	result.Cookies = browserk.DiffCookies(result.Cookies, cookies)
 	result.StorageEvents = browser.GetStorageEvents()
 	result.ConsoleEvents = browser.GetConsoleEvents()
+	result.BlockedRequests = browser.GetBlockedRequests()
+	result.AuthChallenges = browser.GetAuthChallenges()
+	result.URLChanges = browser.GetURLChanges()
 	result.Hash()
 }
 
@@ -130,6 +162,221 @@ func (b *BrowserkCrawler) snapshot(bctx *browserk.Context, browser browserk.Brow
 	return diff
 }
 
+// ScrollNavs scrolls the page while it is taller than the viewport until a scroll adds no new elements
+// (or MaxScrolls is reached) so content behind infinite scroll and lazy loading is found. Every scroll that finds new elements is recorded
+// as a scroll navigation chained from the previous one, and the new elements are navigated to from it
+// so replaying them scrolls as far first. Navigations are created after every scroll since
+// virtualized lists may remove elements again once they are scrolled out of view
+func (b *BrowserkCrawler) ScrollNavs(bctx *browserk.Context, entry *browserk.Navigation, browser browserk.Browser) []*browserk.Navigation {
+	navs := make([]*browserk.Navigation, 0)
+	maxScrolls := b.cfg.MaxScrolls
+	if maxScrolls == 0 {
+		maxScrolls = defaultMaxScrolls
+	} else if maxScrolls < 0 {
+		return navs
+	}
+
+	seen := b.snapshot(bctx, browser)
+	from := entry
+	for i := 0; i < maxScrolls; i++ {
+		if !isScrollable(bctx, browser) {
+			bctx.Log.Debug().Int("scroll", i).Msg("document fits the viewport, not scrolling")
+			break
+		}
+
+		scrollCtx, cancel := context.WithTimeout(bctx.Ctx, time.Second*15)
+		_, causedLoad, err := browser.ExecuteAction(scrollCtx, &browserk.Action{Type: browserk.ActScroll})
+		cancel()
+		if err != nil || causedLoad {
+			bctx.Log.Debug().Err(err).Bool("caused_load", causedLoad).Msg("stopped scrolling")
+			break
+		}
+
+		// give lazy loaded content a chance to arrive
+		select {
+		case <-time.After(scrollWait):
+		case <-bctx.Ctx.Done():
+			return navs
+		}
+
+		current := b.snapshot(bctx, browser)
+		added := seen.Added(current)
+		if added == 0 {
+			break
+		}
+		bctx.Log.Debug().Int("scroll", i+1).Int("added", added).Msg("scrolling found new elements")
+		scroll := browserk.NewNavigationFromScroll(from, browserk.TrigCrawler)
+		navs = append(navs, scroll)
+		navs = append(navs, b.FindNewNav(bctx, seen, scroll, browser)...)
+		seen.Merge(current)
+		from = scroll
+	}
+	return navs
+}
+
+// isScrollable returns true if the document is taller than the viewport
+func isScrollable(bctx *browserk.Context, browser browserk.Browser) bool {
+	result, _, err := browser.ExecuteAction(bctx.Ctx, &browserk.Action{Type: browserk.ActExecuteJS, Input: []byte(scrollableJS)})
+	if err != nil || len(result) == 0 {
+		return false
+	}
+
+	scrollable := false
+	if err := json.Unmarshal(result, &scrollable); err != nil {
+		return false
+	}
+	return scrollable
+}
+
+// TypeAheadNavs creates navigations that type form appropriate text into new type-ahead and
+// search-as-you-type inputs, and text inputs listening for key events, so the requests they fire are captured
+func (b *BrowserkCrawler) TypeAheadNavs(bctx *browserk.Context, diff *ElementDiffer, entry *browserk.Navigation, browser browserk.Browser) []*browserk.Navigation {
+	navs := make([]*browserk.Navigation, 0)
+	candidates := make([]*browserk.HTMLElement, 0)
+
+	if inputs, err := browser.FindElements("input"); err == nil {
+		for _, ele := range inputs {
+			if IsTypeAhead(ele) {
+				candidates = append(candidates, ele)
+			}
+		}
+	}
+
+	if interactables, err := browser.FindInteractables(); err == nil {
+		for _, ele := range interactables {
+			if IsTextEntry(ele) && hasKeyEvent(ele) {
+				candidates = append(candidates, ele)
+			}
+		}
+	}
+
+	seen := make(map[string]struct{})
+	for _, ele := range candidates {
+		hash := ele.Hash()
+		if _, ok := seen[string(hash)]; ok || diff.Has(ele.Type, hash) {
+			continue
+		}
+		seen[string(hash)] = struct{}{}
+
+		text := browserk.DefaultFormValues.SearchTerm
+		if bctx.FormHandler != nil {
+			text = bctx.FormHandler.Suggest(ele)
+		}
+
+		if text == "" {
+			continue
+		}
+		bctx.Log.Debug().Str("name", ele.GetAttribute("name")).Str("text", text).Msg("adding type-ahead navigation")
+		input := &browserk.KeyboardInput{Keys: text}
+		navs = append(navs, browserk.NewNavigationFromKeyboard(entry, browserk.TrigCrawler, ele, browserk.ActSendKeys, input))
+	}
+	return navs
+}
+
+// IsTextEntry returns true if the element accepts typed text
+func IsTextEntry(ele *browserk.HTMLElement) bool {
+	switch ele.Type {
+	case browserk.TEXTAREA:
+		return true
+	case browserk.INPUT:
+		switch strings.ToLower(ele.GetAttribute("type")) {
+		case "", "text", "search", "email", "url", "tel":
+			return true
+		}
+	}
+	return ele.GetAttribute("contenteditable") == "true"
+}
+
+// IsTypeAhead returns true if the element is a text input that looks like it searches or
+// suggests as you type
+func IsTypeAhead(ele *browserk.HTMLElement) bool {
+	if !IsTextEntry(ele) || IsOneTimeCodeElement(ele) {
+		return false
+	}
+
+	if strings.ToLower(ele.GetAttribute("type")) == "search" || ele.GetAttribute("list") != "" ||
+		ele.GetAttribute("role") == "combobox" || ele.GetAttribute("role") == "searchbox" {
+		return true
+	}
+
+	if autocomplete := ele.GetAttribute("aria-autocomplete"); autocomplete != "" && autocomplete != "none" {
+		return true
+	}
+
+	details := NewInputDetails(ele)
+	return SearchTermRe.MatchString(details.Name) || SearchTermRe.MatchString(details.ID) ||
+		SearchTermRe.MatchString(details.AriaLabel+details.PlaceHolder)
+}
+
+func hasKeyEvent(ele *browserk.HTMLElement) bool {
+	for _, eventType := range ele.Events {
+		switch eventType {
+		case browserk.HTMLEventkeydown, browserk.HTMLEventkeypress, browserk.HTMLEventkeyup:
+			return true
+		}
+	}
+	return false
+}
+
+// RouteNavs creates navigations that directly load the in scope client side routes
+// (pushState/replaceState/popstate/hashchange) the action caused
+func (b *BrowserkCrawler) RouteNavs(bctx *browserk.Context, entry *browserk.Navigation, result *browserk.NavigationResult) []*browserk.Navigation {
+	navs := make([]*browserk.Navigation, 0)
+	seen := make(map[string]struct{})
+	for _, change := range result.URLChanges {
+		if _, ok := seen[change.URL]; ok {
+			continue
+		}
+		seen[change.URL] = struct{}{}
+
+		if entry.Action.Type == browserk.ActLoadURL && string(entry.Action.Input) == change.URL {
+			continue
+		}
+
+		if bctx.Scope.Check(change.URL) != browserk.InScope {
+			continue
+		}
+		bctx.Log.Info().Str("type", change.Type).Str("url", change.URL).Msg("adding client side route")
+		navs = append(navs, browserk.NewNavigationFromURL(entry, browserk.TrigCrawler, change.URL))
+	}
+	return navs
+}
+
+// ScriptNavs creates navigations that directly load the in scope endpoints and routes found in
+// scripts we have not analyzed before, relative endpoints are resolved against the page (and its base href)
+func (b *BrowserkCrawler) ScriptNavs(bctx *browserk.Context, entry *browserk.Navigation, pageURL, baseHref string, scripts []*browserk.ScriptEvent) []*browserk.Navigation {
+	navs := make([]*browserk.Navigation, 0)
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return navs
+	}
+
+	if href, err := url.Parse(baseHref); err == nil && baseHref != "" {
+		base = base.ResolveReference(href)
+	}
+
+	for _, endpoint := range b.js.Endpoints(scripts) {
+		// loading the url would send a GET the application never makes
+		if endpoint.Method != http.MethodGet {
+			bctx.Log.Debug().Str("endpoint", endpoint.URL).Str("method", endpoint.Method).Msg("skipping non GET endpoint found in script")
+			continue
+		}
+
+		ref, err := url.Parse(endpoint.URL)
+		if err != nil {
+			continue
+		}
+
+		target := base.ResolveReference(ref).String()
+		if bctx.Scope.Check(target) != browserk.InScope {
+			continue
+		}
+		bctx.Log.Debug().Str("endpoint", endpoint.URL).Str("url", target).Msg("adding endpoint found in script")
+		navs = append(navs, browserk.NewNavigationFromURL(entry, browserk.TrigCrawler, target))
+	}
+	return navs
+}
+
 // FindNewNav potentials TODO: get navigation entry metadata (is vuejs/react etc) to be more specific
 func (b *BrowserkCrawler) FindNewNav(bctx *browserk.Context, diff *ElementDiffer, entry *browserk.Navigation, browser browserk.Browser) []*browserk.Navigation {
 	navs := make([]*browserk.Navigation, 0)
@@ -143,11 +390,22 @@ func (b *BrowserkCrawler) FindNewNav(bctx *browserk.Context, diff *ElementDiffer
 	}
 
 	for _, form := range formElements {
-		scope := bctx.Scope.ResolveBaseHref(baseHref, form.GetAttribute("action"))
-		if scope == browserk.InScope && !diff.Has(browserk.FORM, form.Hash()) {
+		if diff.Has(browserk.FORM, form.Hash()) {
+			continue
+		}
+
+		scope := bctx.Scope.CheckForm(baseHref, form)
+		if scope == browserk.InScope {
 			nav := browserk.NewNavigationFromForm(entry, browserk.TrigCrawler, form)
 			bctx.FormHandler.Fill(form)
 			navs = append(navs, nav)
+		} else if scope == browserk.ExcludedFromScope {
+			// record it so we know it exists, but it will never be picked up
+			bctx.Log.Info().Str("action", form.GetAttribute("action")).Str("id", form.GetAttribute("id")).Msg("form is excluded, skipping")
+			nav := browserk.NewNavigationFromForm(entry, browserk.TrigCrawler, form)
+			nav.State = browserk.NavSkipped
+			nav.Scope = scope
+			navs = append(navs, nav)
 		} /*else {
 			bctx.Log.Debug().Str("href", baseHref).Str("action", form.GetAttribute("action")).Msg("was out of scope or already found, not creating new nav")
 		} */
@@ -204,6 +462,10 @@ func (b *BrowserkCrawler) FindNewNav(bctx *browserk.Context, diff *ElementDiffer
 					case browserk.HTMLEventmouseover, browserk.HTMLEventmouseenter, browserk.HTMLEventmouseleave, browserk.HTMLEventmouseout:
 						actType = browserk.ActMouseOverAndOut
 					case browserk.HTMLEventkeydown, browserk.HTMLEventkeypress, browserk.HTMLEventkeyup:
+						// text entry is typed into by TypeAheadNavs instead of pressing enter
+						if IsTextEntry(ele) {
+							continue
+						}
 						actType = browserk.ActSendKeys
 					case browserk.HTMLEventwheel:
 						actType = browserk.ActMouseWheel
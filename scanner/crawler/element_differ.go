@@ -33,3 +33,25 @@ func (e *ElementDiffer) Has(element browserk.HTMLElementType, hash []byte) bool
 	_, exist := e.elements[element][string(hash)]
 	return exist
 }
+
+// Merge the hashes of other into this differ
+func (e *ElementDiffer) Merge(other *ElementDiffer) {
+	for element, hashes := range other.elements {
+		for hash := range hashes {
+			e.Add(element, []byte(hash))
+		}
+	}
+}
+
+// Added returns the number of hashes in after that this differ does not have
+func (e *ElementDiffer) Added(after *ElementDiffer) int {
+	added := 0
+	for element, hashes := range after.elements {
+		for hash := range hashes {
+			if !e.Has(element, []byte(hash)) {
+				added++
+			}
+		}
+	}
+	return added
+}
@@ -13,7 +13,8 @@ import (
 
 // CrawlerFormHandler handles filling forms
 type CrawlerFormHandler struct {
-	formData *browserk.FormData
+	formData    *browserk.FormData
+	credentials *browserk.Credentials
 }
 
 // NewCrawlerFormHandler will fill forms based on the provided formData and determining
@@ -22,6 +23,11 @@ func NewCrawlerFormHandler(formData *browserk.FormData) *CrawlerFormHandler {
 	return &CrawlerFormHandler{formData: formData}
 }
 
+// SetCredentials so one time code inputs can be filled with a fresh TOTP code
+func (c *CrawlerFormHandler) SetCredentials(credentials *browserk.Credentials) {
+	c.credentials = credentials
+}
+
 // Init the form filler
 // TODO: validate form data isn't empty etc
 func (c *CrawlerFormHandler) Init() error {
@@ -31,22 +37,23 @@ func (c *CrawlerFormHandler) Init() error {
 // InputDetails for an input tag
 // TODO: Handle file
 type InputDetails struct {
-	Name        string
-	ID          string
-	Type        string
-	PlaceHolder string
-	AriaLabel   string
-	LabelText   string
-	Min         string
-	Max         string
-	Multiple    bool
-	Required    bool
-	Step        string
-	Src         string
-	Alt         string
-	Pattern     string
-	Title       string
-	Unchecked   bool
+	Name         string
+	ID           string
+	Type         string
+	PlaceHolder  string
+	AriaLabel    string
+	LabelText    string
+	Min          string
+	Max          string
+	Multiple     bool
+	Required     bool
+	Step         string
+	Src          string
+	Alt          string
+	Pattern      string
+	Title        string
+	Autocomplete string
+	Unchecked    bool
 }
 
 // FormContext for auto filling easier
@@ -89,6 +96,40 @@ func (f *FormContext) AddInput(hash string, details *InputDetails) {
 	}
 }
 
+// NewInputDetails from an input element's attributes
+func NewInputDetails(ele *browserk.HTMLElement) *InputDetails {
+	return &InputDetails{
+		Name:        strings.ToLower(ele.GetAttribute("name")),
+		ID:          strings.ToLower(ele.GetAttribute("id")),
+		AriaLabel:   strings.ToLower(ele.GetAttribute("aria-label")),
+		Type:        strings.ToLower(ele.GetAttribute("type")),
+		PlaceHolder: strings.ToLower(ele.GetAttribute("placeholder")),
+		Min:         ele.GetAttribute("min"),
+		Max:         ele.GetAttribute("max"),
+		Multiple:    false,
+		Required:    false,
+		Step:        ele.GetAttribute("step"),
+		Src:         ele.GetAttribute("src"),
+		Alt:         ele.GetAttribute("alt"),
+		Pattern:     ele.GetAttribute("pattern"),
+		Title:       strings.ToLower(ele.GetAttribute("title")),
+		// autocomplete may have multiple tokens, one-time-code is the only one we care about
+		Autocomplete: strings.ToLower(ele.GetAttribute("autocomplete")),
+	}
+}
+
+// Suggest context relevant text for a single input or textarea outside of filling a form
+// (type-ahead, search-as-you-type)
+func (c *CrawlerFormHandler) Suggest(ele *browserk.HTMLElement) string {
+	switch ele.Type {
+	case browserk.TEXTAREA:
+		return c.formData.CommentText
+	case browserk.INPUT:
+		return c.GetSuggestedInput(NewInputDetails(ele))
+	}
+	return c.suggestTextInput(NewInputDetails(ele))
+}
+
 // Fill the form with context relevant data
 func (c *CrawlerFormHandler) Fill(form *browserk.HTMLFormElement) {
 	formContext := c.CreateFormContext(form)
@@ -107,6 +148,12 @@ func (c *CrawlerFormHandler) GetSuggestedInput(input *InputDetails) string {
 	isStart := DateStartRe.MatchString(label)
 	isEnd := DateEndRe.MatchString(label)
 	now := time.Now()
+
+	if IsOneTimeCode(input) {
+		if code, err := c.credentials.TOTP(now); err == nil {
+			return code
+		}
+	}
 	// check element type first as that's the heighest weight and will allow us to
 	// exit out early
 	switch input.Type {
@@ -222,6 +269,38 @@ func (c *CrawlerFormHandler) GetSuggestedInput(input *InputDetails) string {
 	return c.suggestTextInput(input)
 }
 
+// IsOneTimeCode returns true if the input expects a one time (MFA) code
+func IsOneTimeCode(input *InputDetails) bool {
+	if strings.Contains(input.Autocomplete, "one-time-code") {
+		return true
+	}
+
+	switch input.Type {
+	case "", "text", "number", "tel", "password":
+	default:
+		return false
+	}
+
+	label := input.AriaLabel + input.LabelText + input.PlaceHolder
+	return OneTimeCodeRe.MatchString(input.Name) || OneTimeCodeRe.MatchString(input.ID) ||
+		OneTimePwdRe.MatchString(input.Name) || OneTimePwdRe.MatchString(label)
+}
+
+// IsOneTimeCodeElement returns true if the element is an input that expects a one time (MFA) code
+func IsOneTimeCodeElement(ele *browserk.HTMLElement) bool {
+	if ele.Type != browserk.INPUT {
+		return false
+	}
+	return IsOneTimeCode(&InputDetails{
+		Name:         strings.ToLower(ele.GetAttribute("name")),
+		ID:           strings.ToLower(ele.GetAttribute("id")),
+		AriaLabel:    strings.ToLower(ele.GetAttribute("aria-label")),
+		Type:         strings.ToLower(ele.GetAttribute("type")),
+		PlaceHolder:  strings.ToLower(ele.GetAttribute("placeholder")),
+		Autocomplete: strings.ToLower(ele.GetAttribute("autocomplete")),
+	})
+}
+
 // there be dragons here
 func (c *CrawlerFormHandler) suggestTextInput(input *InputDetails) string {
 	label := input.AriaLabel + input.LabelText + input.PlaceHolder
@@ -448,22 +527,7 @@ func (c *CrawlerFormHandler) CreateFormContext(form *browserk.HTMLFormElement) *
 				continue
 			}
 
-			formContext.AddInput(string(ele.Hash()), &InputDetails{
-				Name:        strings.ToLower(ele.GetAttribute("name")),
-				ID:          strings.ToLower(ele.GetAttribute("id")),
-				AriaLabel:   strings.ToLower(ele.GetAttribute("aria-label")),
-				Type:        strings.ToLower(ele.GetAttribute("type")),
-				PlaceHolder: strings.ToLower(ele.GetAttribute("placeholder")),
-				Min:         ele.GetAttribute("min"),
-				Max:         ele.GetAttribute("max"),
-				Multiple:    false,
-				Required:    false,
-				Step:        ele.GetAttribute("step"),
-				Src:         ele.GetAttribute("src"),
-				Alt:         ele.GetAttribute("alt"),
-				Pattern:     ele.GetAttribute("pattern"),
-				Title:       strings.ToLower(ele.GetAttribute("title")),
-			})
+			formContext.AddInput(string(ele.Hash()), NewInputDetails(ele))
 		case browserk.TEXTAREA:
 			formContext.AddInput(string(ele.Hash()), &InputDetails{
 				AriaLabel:   ele.GetAttribute("aria-label"),
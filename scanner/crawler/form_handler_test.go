@@ -59,3 +59,35 @@ func TestFormContext(t *testing.T) {
 		}
 	}
 }
+
+func TestOneTimeCode(t *testing.T) {
+	var tests = []struct {
+		input    *crawler.InputDetails
+		expected bool
+	}{
+		{&crawler.InputDetails{Type: "text", Autocomplete: "one-time-code"}, true},
+		{&crawler.InputDetails{Type: "text", Name: "otp"}, true},
+		{&crawler.InputDetails{Type: "number", Name: "mfa_code"}, true},
+		{&crawler.InputDetails{Type: "text", Name: "code"}, true},
+		{&crawler.InputDetails{Type: "text", Name: "verification-code"}, true},
+		{&crawler.InputDetails{Type: "text", ID: "totp"}, true},
+		{&crawler.InputDetails{Type: "text", Name: "postal_code"}, false},
+		{&crawler.InputDetails{Type: "text", Name: "zipcode"}, false},
+		{&crawler.InputDetails{Type: "text", Name: "promo_code"}, false},
+		{&crawler.InputDetails{Type: "text", Name: "country_code"}, false},
+		{&crawler.InputDetails{Type: "hidden", Name: "otp"}, false},
+	}
+
+	for _, tt := range tests {
+		if got := crawler.IsOneTimeCode(tt.input); got != tt.expected {
+			t.Fatalf("%#v expected %v got %v\n", tt.input, tt.expected, got)
+		}
+	}
+
+	formHandler := crawler.NewCrawlerFormHandler(testFormData)
+	formHandler.SetCredentials(&browserk.Credentials{TOTPSecret: "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"})
+	code := formHandler.GetSuggestedInput(&crawler.InputDetails{Type: "text", Autocomplete: "one-time-code"})
+	if len(code) != browserk.TOTPDigits {
+		t.Fatalf("expected a totp code got %s\n", code)
+	}
+}
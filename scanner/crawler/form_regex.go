@@ -146,6 +146,7 @@ var UrlSearchActionRe = regexp.MustCompile("/search(/|((\\w*\\.\\w+)?$))")
 var SocialSecurityRe = regexp.MustCompile("ssn|social.?security.?(num(ber)?|#)*")
 var PasswordRe = regexp.MustCompile("password|passwd|pwd|pass|パスワード")
 var OneTimePwdRe = regexp.MustCompile("one.?time|sms.?(code|token|password|pwd|pass)")
+var OneTimeCodeRe = regexp.MustCompile("^(t?otp|mfa|2fa|code|token)$|(^|[^a-z])(t?otp|mfa|2fa)([^a-z]|$)|(otp|mfa|auth|verification|verify|security|authenticator).?(code|token)")
 
 // Others
 var CommentTitleRe = regexp.MustCompile("title|sub(ject)")
@@ -0,0 +1,181 @@
+package crawler
+
+import (
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+
+	"gitlab.com/browserker/browserk"
+)
+
+const maxEndpointLength = 2048
+
+var (
+	// quoted absolute urls or root relative paths
+	jsURLRe = regexp.MustCompile("[\"'`]((?:https?:)?//[^\"'`\\s<>]+|/[a-zA-Z0-9_\\-][^\"'`\\s<>]*)[\"'`]")
+	// fetch("...", {method: "POST"}), axios("..."), axios.get("..."), $.get("...") etc
+	jsCallRe = regexp.MustCompile("(\\bfetch|\\baxios(?:\\.(?:get|post|put|patch|delete|head|options|request))?|\\$\\.(?:ajax|get|post|getJSON))\\(\\s*[\"'`]([^\"'`\\s]+)[\"'`](?:\\s*,\\s*\\{([^}]*)\\})?")
+	// method: "POST" or type: "POST" in fetch/axios/$.ajax options
+	jsMethodRe = regexp.MustCompile("\\b(?:method|type)\\s*:\\s*[\"'`]([a-zA-Z]+)[\"'`]")
+	// xhr.open("GET", "...")
+	jsXHRRe = regexp.MustCompile("\\.open\\(\\s*[\"'`]((?i:get|post|put|patch|delete|head|options))[\"'`]\\s*,\\s*[\"'`]([^\"'`\\s]+)[\"'`]")
+	// {path: "/users", component: ...} in vue router, angular and react router route tables
+	// and <Route path="/users" /> once compiled
+	jsRouteRe = regexp.MustCompile("\\bpath\\s*[:=]\\s*[\"'`]([^\"'`\\s]*)[\"'`]")
+)
+
+// static files we don't want to load directly
+var assetExtensions = map[string]struct{}{
+	".js": {}, ".mjs": {}, ".map": {}, ".css": {}, ".png": {}, ".jpg": {}, ".jpeg": {}, ".gif": {},
+	".svg": {}, ".ico": {}, ".webp": {}, ".woff": {}, ".woff2": {}, ".ttf": {}, ".eot": {}, ".mp4": {},
+}
+
+// Endpoint found in a script and the http method it is requested with
+type Endpoint struct {
+	URL    string
+	Method string // GET unless a fetch/xhr/axios/jquery call uses another method
+}
+
+// JSExtractor finds endpoints and routes in scripts, each script is only analyzed once
+type JSExtractor struct {
+	lock sync.Mutex
+	seen map[string]struct{}
+}
+
+// NewJSExtractor that may be shared between crawlers
+func NewJSExtractor() *JSExtractor {
+	return &JSExtractor{seen: make(map[string]struct{})}
+}
+
+// Endpoints of the scripts we have not analyzed before
+func (j *JSExtractor) Endpoints(scripts []*browserk.ScriptEvent) []*Endpoint {
+	endpoints := make([]*Endpoint, 0)
+	for _, script := range scripts {
+		if script.Source == "" {
+			continue
+		}
+
+		key := script.Hash
+		if key == "" {
+			key = script.URL
+		}
+
+		j.lock.Lock()
+		_, seen := j.seen[key]
+		j.seen[key] = struct{}{}
+		j.lock.Unlock()
+
+		if seen {
+			continue
+		}
+		endpoints = append(endpoints, ExtractEndpoints(script.Source)...)
+	}
+	return endpoints
+}
+
+// ExtractEndpoints returns the unique url literals, fetch/xhr/axios/jquery call targets and
+// router paths found in the javascript source. They may be relative and need to be resolved.
+// Calls are matched first so an endpoint keeps the method it's called with
+func ExtractEndpoints(source string) []*Endpoint {
+	endpoints := make([]*Endpoint, 0)
+	seen := make(map[string]struct{})
+	add := func(candidate, method string, isRoute bool) {
+		if isRoute {
+			candidate = routeToPath(candidate)
+		}
+
+		if !validEndpoint(candidate) {
+			return
+		}
+
+		if _, ok := seen[candidate]; ok {
+			return
+		}
+		seen[candidate] = struct{}{}
+		endpoints = append(endpoints, &Endpoint{URL: candidate, Method: method})
+	}
+
+	for _, match := range jsCallRe.FindAllStringSubmatch(source, -1) {
+		add(match[2], callMethod(match[1], match[3]), false)
+	}
+
+	for _, match := range jsXHRRe.FindAllStringSubmatch(source, -1) {
+		add(match[2], strings.ToUpper(match[1]), false)
+	}
+
+	for _, match := range jsURLRe.FindAllStringSubmatch(source, -1) {
+		add(match[1], http.MethodGet, false)
+	}
+
+	for _, match := range jsRouteRe.FindAllStringSubmatch(source, -1) {
+		add(match[1], http.MethodGet, true)
+	}
+	return endpoints
+}
+
+// callMethod returns the method of a fetch/axios/jquery call from the function called
+// (axios.post, $.post) or the method/type of its options
+func callMethod(call, options string) string {
+	switch call {
+	case "$.get", "$.getJSON":
+		return http.MethodGet
+	case "$.post":
+		return http.MethodPost
+	}
+
+	if idx := strings.IndexByte(call, '.'); idx != -1 && strings.HasPrefix(call, "axios") && call != "axios.request" {
+		return strings.ToUpper(call[idx+1:])
+	}
+
+	if match := jsMethodRe.FindStringSubmatch(options); match != nil {
+		return strings.ToUpper(match[1])
+	}
+	return http.MethodGet
+}
+
+// routeToPath replaces :params with a value and removes wildcard segments
+func routeToPath(route string) string {
+	segments := strings.Split(route, "/")
+	kept := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		switch {
+		case strings.HasPrefix(segment, ":"):
+			kept = append(kept, "1")
+		case strings.Contains(segment, "*"):
+			continue
+		default:
+			kept = append(kept, segment)
+		}
+	}
+	return strings.Join(kept, "/")
+}
+
+// validEndpoint filters out templates, regexes, route patterns and static files
+func validEndpoint(candidate string) bool {
+	if candidate == "" || len(candidate) > maxEndpointLength {
+		return false
+	}
+
+	// route patterns (/users/:id, /admin/*) are only valid once converted by routeToPath
+	if strings.Contains(candidate, "*") || strings.Contains(candidate, "/:") {
+		return false
+	}
+
+	if strings.ContainsAny(candidate, "${}\\^|()<> \t\n") {
+		return false
+	}
+
+	lower := strings.ToLower(candidate)
+	if strings.HasPrefix(lower, "javascript:") || strings.HasPrefix(lower, "data:") || strings.HasPrefix(lower, "mailto:") {
+		return false
+	}
+
+	if idx := strings.IndexAny(lower, "?#"); idx != -1 {
+		lower = lower[:idx]
+	}
+
+	_, isAsset := assetExtensions[path.Ext(lower)]
+	return !isAsset
+}
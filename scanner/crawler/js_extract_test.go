@@ -0,0 +1,91 @@
+package crawler_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/rs/zerolog"
+	"gitlab.com/browserker/browserk"
+	"gitlab.com/browserker/mock"
+	"gitlab.com/browserker/scanner"
+	"gitlab.com/browserker/scanner/crawler"
+)
+
+func TestExtractEndpoints(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/js/bundle.js")
+	if err != nil {
+		t.Fatalf("error reading bundle: %s\n", err)
+	}
+
+	expected := []*crawler.Endpoint{
+		{URL: "/api/v1/users?limit=10", Method: "GET"},
+		{URL: "/api/v1/profile", Method: "GET"},
+		{URL: "api/settings", Method: "GET"},
+		{URL: "/api/v1/session", Method: "DELETE"},
+		{URL: "/api/v1/profile/avatar", Method: "PUT"},
+		{URL: "/api/v1/comments", Method: "POST"},
+		{URL: "/legacy/data.json", Method: "GET"},
+		{URL: "/api/v1/login", Method: "POST"},
+		{URL: "/api/v1", Method: "GET"},
+		{URL: "/dashboard", Method: "GET"},
+		{URL: "/", Method: "GET"},
+		{URL: "/users/1", Method: "GET"},
+		{URL: "/admin", Method: "GET"},
+		{URL: "reports", Method: "GET"},
+	}
+
+	endpoints := crawler.ExtractEndpoints(string(data))
+	if !reflect.DeepEqual(endpoints, expected) {
+		t.Fatalf("expected:\n%v\ngot:\n%v\n", spew.Sdump(expected), spew.Sdump(endpoints))
+	}
+}
+
+func TestJSScriptNavs(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/js/bundle.js")
+	if err != nil {
+		t.Fatalf("error reading bundle: %s\n", err)
+	}
+
+	ctx := context.Background()
+	bCtx := mock.Context(ctx)
+	bCtx.Log = &zerolog.Logger{}
+	targetURL, _ := url.Parse("http://example.com/app/")
+	bCtx.Scope = scanner.NewScopeService(targetURL)
+
+	entry := browserk.NewNavigation(browserk.TrigInitial, browserk.NewLoadURLAction("http://example.com/app/"))
+	scripts := []*browserk.ScriptEvent{{ScriptID: "1", URL: "http://example.com/app/bundle.js", Hash: "abc", Source: string(data)}}
+
+	crawl := crawler.New(&browserk.Config{})
+	navs := crawl.ScriptNavs(bCtx, entry, "http://example.com/app/index.html", "", scripts)
+
+	found := make(map[string]struct{})
+	for _, nav := range navs {
+		found[string(nav.Action.Input)] = struct{}{}
+	}
+
+	for _, expected := range []string{"http://example.com/api/v1/users?limit=10", "http://example.com/app/api/settings", "http://example.com/users/1", "http://example.com/app/reports"} {
+		if _, ok := found[expected]; !ok {
+			t.Fatalf("expected nav for %s in %v\n", expected, found)
+		}
+	}
+
+	if _, ok := found["https://cdn.example.com/lib.js"]; ok {
+		t.Fatalf("out of scope endpoint should not be added\n")
+	}
+
+	// loading these would send a GET instead of the method they are called with
+	for _, notExpected := range []string{"http://example.com/api/v1/login", "http://example.com/api/v1/session", "http://example.com/api/v1/comments"} {
+		if _, ok := found[notExpected]; ok {
+			t.Fatalf("non GET endpoint %s should not be added\n", notExpected)
+		}
+	}
+
+	// scripts are only analyzed once
+	if navs = crawl.ScriptNavs(bCtx, entry, "http://example.com/app/index.html", "", scripts); len(navs) != 0 {
+		t.Fatalf("expected already analyzed script to be ignored got %d navs\n", len(navs))
+	}
+}
@@ -0,0 +1,50 @@
+package crawler_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"gitlab.com/browserker/browserk"
+	"gitlab.com/browserker/mock"
+	"gitlab.com/browserker/scanner"
+	"gitlab.com/browserker/scanner/crawler"
+)
+
+func TestRouteNavs(t *testing.T) {
+	ctx := context.Background()
+	bCtx := mock.Context(ctx)
+	bCtx.Log = &zerolog.Logger{}
+	targetURL, _ := url.Parse("http://example.com/app/")
+	bCtx.Scope = scanner.NewScopeService(targetURL)
+
+	entry := browserk.NewNavigation(browserk.TrigInitial, browserk.NewLoadURLAction("http://example.com/app/"))
+	entry.Distance = 1
+
+	result := &browserk.NavigationResult{
+		URLChanges: []*browserk.URLChangeEvent{
+			{Type: "pushState", URL: "http://example.com/app/users"},
+			{Type: "replaceState", URL: "http://example.com/app/users"},
+			{Type: "hashchange", URL: "http://example.com/app/#/settings"},
+			{Type: "popstate", URL: "http://example.com/app/"},
+			{Type: "pushState", URL: "http://other.com/app/"},
+		},
+	}
+
+	navs := crawler.New(&browserk.Config{}).RouteNavs(bCtx, entry, result)
+	if len(navs) != 2 {
+		t.Fatalf("expected 2 route navs got %d\n", len(navs))
+	}
+
+	expected := []string{"http://example.com/app/users", "http://example.com/app/#/settings"}
+	for i, nav := range navs {
+		if nav.Action.Type != browserk.ActLoadURL || string(nav.Action.Input) != expected[i] {
+			t.Fatalf("expected load url %s got %s %s\n", expected[i], browserk.ActionTypeMap[nav.Action.Type], nav.Action.Input)
+		}
+
+		if len(nav.OriginID) != 0 || nav.Distance != 2 {
+			t.Fatalf("expected direct load nav with distance 2 got origin %v distance %d\n", nav.OriginID, nav.Distance)
+		}
+	}
+}
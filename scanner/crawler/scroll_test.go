@@ -0,0 +1,146 @@
+package crawler_test
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"gitlab.com/browserker/browserk"
+	"gitlab.com/browserker/mock"
+	"gitlab.com/browserker/scanner"
+	"gitlab.com/browserker/scanner/crawler"
+)
+
+func TestScrollNavs(t *testing.T) {
+	ctx := context.Background()
+	bCtx := mock.Context(ctx)
+	bCtx.Log = &zerolog.Logger{}
+	targetURL, _ := url.Parse("http://example.com/")
+	bCtx.Scope = scanner.NewScopeService(targetURL)
+
+	entry := browserk.NewNavigation(browserk.TrigInitial, browserk.NewLoadURLAction("http://example.com/feed"))
+
+	// each scroll loads 2 more posts until there are 6, like a virtualized list
+	// only the last 4 are ever in the document
+	scrolls := 0
+	scrollable := []byte("true")
+	b := mock.MakeMockBrowser()
+	b.ExecuteActionFn = func(ctx context.Context, act *browserk.Action) ([]byte, bool, error) {
+		if act.Type == browserk.ActExecuteJS {
+			return scrollable, false, nil
+		}
+
+		if act.Type != browserk.ActScroll || act.Element != nil {
+			t.Fatalf("expected page scroll got %s\n", browserk.ActionTypeMap[act.Type])
+		}
+		scrolls++
+		return nil, false, nil
+	}
+	b.FindElementsFn = func(querySelector string) ([]*browserk.HTMLElement, error) {
+		links := make([]*browserk.HTMLElement, 0)
+		if querySelector != "a" {
+			return links, nil
+		}
+
+		total := 2 + 2*scrolls
+		if total > 6 {
+			total = 6
+		}
+		start := total - 4
+		if start < 0 {
+			start = 0
+		}
+		for i := start; i < total; i++ {
+			href := fmt.Sprintf("/posts/%d", i)
+			links = append(links, &browserk.HTMLElement{Type: browserk.A, Attributes: map[string]string{"href": href}})
+		}
+		return links, nil
+	}
+
+	navs := crawler.New(&browserk.Config{}).ScrollNavs(bCtx, entry, b)
+	// 2 scrolls adding posts, 1 more finding nothing new
+	if scrolls != 3 {
+		t.Fatalf("expected 3 scrolls got %d\n", scrolls)
+	}
+
+	// scroll, posts 2 & 3, scroll, posts 4 & 5
+	if len(navs) != 6 {
+		t.Fatalf("expected 2 scroll navs and 4 navs for posts 2-5 got %d\n", len(navs))
+	}
+
+	byID := map[string]*browserk.Navigation{string(entry.ID): entry}
+	post := 2
+	for i, nav := range navs {
+		byID[string(nav.ID)] = nav
+		if i%3 == 0 {
+			if nav.Action.Type != browserk.ActScroll || nav.Distance != i/3+1 {
+				t.Fatalf("expected scroll nav at distance %d got %s at %d\n", i/3+1, browserk.ActionTypeMap[nav.Action.Type], nav.Distance)
+			}
+			continue
+		}
+
+		expected := fmt.Sprintf("/posts/%d", post)
+		if nav.Action.Element.Attributes["href"] != expected || string(nav.OriginID) != string(navs[i-i%3].ID) {
+			t.Fatalf("expected %s from the scroll before it got %s\n", expected, nav.Action.Element.Attributes["href"])
+		}
+		post++
+	}
+
+	// replaying the path to the last post in a fresh page scrolls to it first
+	path := make([]*browserk.Navigation, 0)
+	for nav := navs[len(navs)-1]; nav != nil; nav = byID[string(nav.OriginID)] {
+		path = append([]*browserk.Navigation{nav}, path...)
+	}
+
+	scrolls = 0
+	b.ExecuteActionFn = func(ctx context.Context, act *browserk.Action) ([]byte, bool, error) {
+		switch act.Type {
+		case browserk.ActExecuteJS:
+			return scrollable, false, nil
+		case browserk.ActScroll:
+			scrolls++
+		case browserk.ActLeftClick:
+			links, _ := b.FindElementsFn("a")
+			for _, link := range links {
+				if link.Attributes["href"] == act.Element.Attributes["href"] {
+					return nil, false, nil
+				}
+			}
+			return nil, false, fmt.Errorf("%s not found", act.Element.Attributes["href"])
+		}
+		return nil, false, nil
+	}
+
+	c := crawler.New(&browserk.Config{MaxScrolls: -1})
+	for _, nav := range path {
+		if _, _, err := c.Process(bCtx, b, nav, false); err != nil {
+			t.Fatalf("failed to replay %s: %s\n", browserk.ActionTypeMap[nav.Action.Type], err)
+		}
+	}
+
+	if len(path) != 4 || scrolls != 2 {
+		t.Fatalf("expected load, 2 scrolls and a click got %d steps and %d scrolls\n", len(path), scrolls)
+	}
+
+	scrolls = 0
+	navs = crawler.New(&browserk.Config{MaxScrolls: 1}).ScrollNavs(bCtx, entry, b)
+	if scrolls != 1 || len(navs) != 3 {
+		t.Fatalf("expected 1 scroll and 3 navs got %d and %d\n", scrolls, len(navs))
+	}
+
+	scrolls = 0
+	navs = crawler.New(&browserk.Config{MaxScrolls: -1}).ScrollNavs(bCtx, entry, b)
+	if scrolls != 0 || len(navs) != 0 {
+		t.Fatalf("expected scrolling to be disabled got %d scrolls\n", scrolls)
+	}
+
+	// pages that fit the viewport are not scrolled
+	scrolls = 0
+	scrollable = []byte("false")
+	navs = crawler.New(&browserk.Config{}).ScrollNavs(bCtx, entry, b)
+	if scrolls != 0 || len(navs) != 0 {
+		t.Fatalf("expected a page that fits the viewport to not be scrolled got %d scrolls\n", scrolls)
+	}
+}
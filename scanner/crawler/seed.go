@@ -0,0 +1,226 @@
+package crawler
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"gitlab.com/browserker/browserk"
+)
+
+const (
+	seedTimeout     = time.Second * 10
+	maxSeedBody     = 10 * 1024 * 1024 // sitemaps may be up to 50MB but we don't need all of it
+	maxSitemapDepth = 3                // sitemap indexes pointing to sitemap indexes
+	maxSeedURLs     = 5000
+)
+
+// sitemap is either a urlset or a sitemapindex, both have locs
+type sitemap struct {
+	XMLName  xml.Name
+	URLs     []sitemapLoc `xml:"url"`
+	Sitemaps []sitemapLoc `xml:"sitemap"`
+}
+
+type sitemapLoc struct {
+	Loc string `xml:"loc"`
+}
+
+// Seeder finds urls our crawler may never be linked to from robots.txt, sitemap.xml and
+// /.well-known/security.txt
+type Seeder struct {
+	client   *http.Client
+	scope    browserk.ScopeService
+	urls     []string
+	seen     map[string]struct{}
+	sitemaps map[string]struct{}
+}
+
+// NewSeeder only returning (and fetching) urls that are in scope
+func NewSeeder(scope browserk.ScopeService) *Seeder {
+	return &Seeder{
+		client:   &http.Client{Timeout: seedTimeout},
+		scope:    scope,
+		urls:     make([]string, 0),
+		seen:     make(map[string]struct{}),
+		sitemaps: make(map[string]struct{}),
+	}
+}
+
+// Seed returns the in scope urls found for the target
+func (s *Seeder) Seed(ctx context.Context, target *url.URL) []string {
+	base := &url.URL{Scheme: target.Scheme, Host: target.Host}
+
+	sitemaps := s.robots(ctx, base.ResolveReference(&url.URL{Path: "/robots.txt"}))
+	sitemaps = append(sitemaps, base.ResolveReference(&url.URL{Path: "/sitemap.xml"}).String())
+	for _, sitemapURL := range sitemaps {
+		s.sitemap(ctx, sitemapURL, 0)
+	}
+
+	s.securityTxt(ctx, base.ResolveReference(&url.URL{Path: "/.well-known/security.txt"}))
+	return s.urls
+}
+
+// robots adds Allow/Disallow paths and returns the Sitemap urls
+func (s *Seeder) robots(ctx context.Context, robotsURL *url.URL) []string {
+	sitemaps := make([]string, 0)
+	body, err := s.get(ctx, robotsURL.String())
+	if err != nil {
+		return sitemaps
+	}
+	defer body.Close()
+
+	scanner := bufio.NewScanner(body)
+	for scanner.Scan() {
+		field, value := splitField(scanner.Text())
+		switch field {
+		case "allow", "disallow":
+			// wildcards and end anchors are for matching, only keep the literal prefix
+			if idx := strings.IndexAny(value, "*$"); idx != -1 {
+				value = value[:idx]
+			}
+			if value == "" || value == "/" {
+				continue
+			}
+			if ref, err := url.Parse(value); err == nil {
+				s.add(robotsURL.ResolveReference(ref).String())
+			}
+		case "sitemap":
+			if ref, err := url.Parse(value); err == nil {
+				sitemaps = append(sitemaps, robotsURL.ResolveReference(ref).String())
+			}
+		}
+	}
+	return sitemaps
+}
+
+// sitemap adds the urls of a urlset and follows sitemap indexes
+func (s *Seeder) sitemap(ctx context.Context, sitemapURL string, depth int) {
+	if depth > maxSitemapDepth {
+		return
+	}
+
+	if _, ok := s.sitemaps[sitemapURL]; ok {
+		return
+	}
+	s.sitemaps[sitemapURL] = struct{}{}
+
+	if s.scope.Check(sitemapURL) == browserk.ExcludedFromScope {
+		return
+	}
+
+	body, err := s.get(ctx, sitemapURL)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	var reader io.Reader = body
+	if strings.HasSuffix(strings.ToLower(sitemapURL), ".gz") {
+		gz, err := gzip.NewReader(body)
+		if err != nil {
+			log.Debug().Err(err).Str("url", sitemapURL).Msg("invalid gzipped sitemap")
+			return
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	sm := &sitemap{}
+	if err := xml.NewDecoder(reader).Decode(sm); err != nil {
+		log.Debug().Err(err).Str("url", sitemapURL).Msg("invalid sitemap")
+		return
+	}
+
+	for _, loc := range sm.URLs {
+		s.add(strings.TrimSpace(loc.Loc))
+	}
+
+	for _, loc := range sm.Sitemaps {
+		s.sitemap(ctx, strings.TrimSpace(loc.Loc), depth+1)
+	}
+}
+
+// securityTxt adds any url values (Policy, Acknowledgments, Hiring etc) of the security.txt
+func (s *Seeder) securityTxt(ctx context.Context, securityURL *url.URL) {
+	body, err := s.get(ctx, securityURL.String())
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	scanner := bufio.NewScanner(body)
+	for scanner.Scan() {
+		_, value := splitField(scanner.Text())
+		if strings.HasPrefix(value, "http://") || strings.HasPrefix(value, "https://") {
+			s.add(value)
+		}
+	}
+}
+
+// add the url if it's in scope and we haven't seen it
+func (s *Seeder) add(candidate string) {
+	if len(s.urls) >= maxSeedURLs {
+		return
+	}
+
+	u, err := url.Parse(candidate)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return
+	}
+	u.Fragment = ""
+	normalized := u.String()
+
+	if _, ok := s.seen[normalized]; ok {
+		return
+	}
+	s.seen[normalized] = struct{}{}
+
+	if s.scope.Check(normalized) == browserk.InScope {
+		s.urls = append(s.urls, normalized)
+	}
+}
+
+// get the url returning the body only if it was successful
+func (s *Seeder) get(ctx context.Context, target string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		log.Debug().Err(err).Str("url", target).Msg("failed to get seed file")
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status %d for %s", resp.StatusCode, target)
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(resp.Body, maxSeedBody), resp.Body}, nil
+}
+
+// splitField splits a "Field: value" line, ignoring comments, the field is lowercased
+func splitField(line string) (string, string) {
+	if idx := strings.IndexByte(line, '#'); idx != -1 {
+		line = line[:idx]
+	}
+
+	idx := strings.IndexByte(line, ':')
+	if idx == -1 {
+		return "", ""
+	}
+	return strings.ToLower(strings.TrimSpace(line[:idx])), strings.TrimSpace(line[idx+1:])
+}
@@ -0,0 +1,54 @@
+package crawler_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"gitlab.com/browserker/scanner"
+	"gitlab.com/browserker/scanner/crawler"
+)
+
+func TestSeed(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		file := filepath.Base(r.URL.Path)
+		data, err := ioutil.ReadFile(filepath.Join("testdata/seed", file))
+		if err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(strings.Replace(string(data), "{{URL}}", srv.URL, -1)))
+	}))
+	defer srv.Close()
+
+	target, _ := url.Parse(srv.URL + "/app")
+	urls := crawler.NewSeeder(scanner.NewScopeService(target)).Seed(context.Background(), target)
+	sort.Strings(urls)
+
+	expected := []string{
+		srv.URL + "/admin/",
+		srv.URL + "/legacy",
+		srv.URL + "/orphaned",
+		srv.URL + "/private/",
+		srv.URL + "/products/1",
+		srv.URL + "/search?q=",
+		srv.URL + "/security-policy",
+	}
+
+	if len(urls) != len(expected) {
+		t.Fatalf("expected %d urls got %d: %v\n", len(expected), len(urls), urls)
+	}
+
+	for i, u := range urls {
+		if u != expected[i] {
+			t.Fatalf("expected %s got %s\n", expected[i], u)
+		}
+	}
+}
@@ -0,0 +1,14 @@
+!function(){"use strict";
+var api={base:"/api/v1"};
+function loadUsers(){return fetch("/api/v1/users?limit=10").then(function(r){return r.json()})}
+function loadOrders(id){return fetch(`/api/v1/orders/${id}`)}
+axios.get("/api/v1/profile");axios("api/settings");
+var xhr=new XMLHttpRequest;xhr.open("POST","/api/v1/login",!0);
+fetch("/api/v1/session",{method:"DELETE",credentials:"include"});axios.put("/api/v1/profile/avatar");$.post("/api/v1/comments");
+$.getJSON("/legacy/data.json");
+var cdn="https://cdn.example.com/lib.js",logo="/static/logo.png",re=/\/(\d+)\//g;
+var contact="mailto:admin@example.com";
+var routes=[{path:"/",component:Home},{path:"/users/:id",component:User},{path:"/admin/*",component:Admin},
+{path:"reports",component:Reports},{path:"**",redirectTo:""}];
+React.createElement(Route,{path:"/dashboard",element:Dash});
+}();
@@ -0,0 +1,89 @@
+package crawler_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"gitlab.com/browserker/browserk"
+	"gitlab.com/browserker/mock"
+	"gitlab.com/browserker/scanner"
+	"gitlab.com/browserker/scanner/crawler"
+)
+
+func TestTypeAheadNavs(t *testing.T) {
+	ctx := context.Background()
+	bCtx := mock.Context(ctx)
+	bCtx.Log = &zerolog.Logger{}
+	bCtx.FormHandler = crawler.NewCrawlerFormHandler(&browserk.DefaultFormValues)
+	targetURL, _ := url.Parse("http://example.com/")
+	bCtx.Scope = scanner.NewScopeService(targetURL)
+
+	entry := browserk.NewNavigation(browserk.TrigInitial, browserk.NewLoadURLAction("http://example.com/"))
+
+	search := &browserk.HTMLElement{Type: browserk.INPUT, Attributes: map[string]string{"type": "search", "name": "q"}}
+	city := &browserk.HTMLElement{Type: browserk.INPUT, Attributes: map[string]string{"name": "city", "role": "combobox"}}
+	email := &browserk.HTMLElement{Type: browserk.INPUT, Attributes: map[string]string{"type": "email", "name": "email"},
+		Events: map[string]browserk.HTMLEventType{"keyup": browserk.HTMLEventkeyup}}
+	plain := &browserk.HTMLElement{Type: browserk.INPUT, Attributes: map[string]string{"name": "nickname"}}
+	checkbox := &browserk.HTMLElement{Type: browserk.INPUT, Attributes: map[string]string{"type": "checkbox", "name": "search"}}
+	shortcut := &browserk.HTMLElement{Type: browserk.DIV, Attributes: map[string]string{"id": "app"},
+		Events: map[string]browserk.HTMLEventType{"keydown": browserk.HTMLEventkeydown}}
+
+	b := mock.MakeMockBrowser()
+	b.FindElementsFn = func(querySelector string) ([]*browserk.HTMLElement, error) {
+		if querySelector == "input" {
+			return []*browserk.HTMLElement{search, city, email, plain, checkbox}, nil
+		}
+		return make([]*browserk.HTMLElement, 0), nil
+	}
+	b.FindInteractablesFn = func() ([]*browserk.HTMLElement, error) {
+		return []*browserk.HTMLElement{search, email, shortcut}, nil
+	}
+
+	navs := crawler.New(&browserk.Config{}).TypeAheadNavs(bCtx, crawler.NewElementDiffer(), entry, b)
+	expected := map[string]string{
+		"q":     browserk.DefaultFormValues.SearchTerm,
+		"city":  browserk.DefaultFormValues.City,
+		"email": browserk.DefaultFormValues.Email,
+	}
+
+	if len(navs) != len(expected) {
+		t.Fatalf("expected %d type-ahead navs got %d\n", len(expected), len(navs))
+	}
+
+	for _, nav := range navs {
+		name := nav.Action.Element.GetAttribute("name")
+		if nav.Action.Type != browserk.ActSendKeys {
+			t.Fatalf("expected send keys for %s got %s\n", name, browserk.ActionTypeMap[nav.Action.Type])
+		}
+
+		if keys := nav.Action.KeyboardInput().Keys; keys != expected[name] {
+			t.Fatalf("expected %s to type %q got %q\n", name, expected[name], keys)
+		}
+	}
+
+	// already existed before the action
+	diff := crawler.NewElementDiffer()
+	diff.Add(search.Type, search.Hash())
+	if navs := crawler.New(&browserk.Config{}).TypeAheadNavs(bCtx, diff, entry, b); len(navs) != 2 {
+		t.Fatalf("expected 2 navs for new inputs got %d\n", len(navs))
+	}
+
+	// key listeners that aren't text entry still press enter
+	navs = crawler.New(&browserk.Config{}).FindNewNav(bCtx, crawler.NewElementDiffer(), entry, b)
+	keyNavs := 0
+	for _, nav := range navs {
+		if nav.Action.Type == browserk.ActSendKeys {
+			keyNavs++
+			if nav.Action.Element != shortcut || len(nav.Action.Input) != 0 {
+				t.Fatalf("expected only the shortcut element to get an enter nav\n")
+			}
+		}
+	}
+
+	if keyNavs != 1 {
+		t.Fatalf("expected 1 enter nav got %d\n", keyNavs)
+	}
+}
@@ -61,6 +61,8 @@ func (c *Container) Call(evt *browserk.PluginEvent) {
 			plugin.OnEvent(evt)
 		} else if evt.Type == browserk.EvtConsole && plugin.Options().ListenConsole {
 			plugin.OnEvent(evt)
+		} else if evt.Type == browserk.EvtAuthChallenge && (plugin.Options().ListenRequests || plugin.Options().ListenResponses) {
+			plugin.OnEvent(evt)
 		}
 	}
 
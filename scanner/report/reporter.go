@@ -1,28 +1,43 @@
 package report
 
 import (
+	"fmt"
 	"io"
+	"sync"
 
 	"gitlab.com/browserker/browserk"
 )
 
 type Reporter struct {
+	lock    *sync.RWMutex
 	reports map[string]map[string]*browserk.Report
 }
 
 func New() *Reporter {
-	return &Reporter{reports: make(map[string]map[string]*browserk.Report, 0)}
+	return &Reporter{lock: &sync.RWMutex{}, reports: make(map[string]map[string]*browserk.Report, 0)}
 }
 
 func (r *Reporter) Add(report *browserk.Report) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
 	key := report.VulnID + report.Evidence.Hash()
-	if _, exist := r.reports[report.VulnID]; exist {
-		r.reports[report.VulnID][key] = report
+	if _, exist := r.reports[report.VulnID]; !exist {
+		r.reports[report.VulnID] = make(map[string]*browserk.Report)
 	}
-	r.reports[report.VulnID] = make(map[string]*browserk.Report)
 	r.reports[report.VulnID][key] = report
 }
 
 func (r *Reporter) Print(writer io.Writer) {
-	return
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	for vulnID, reports := range r.reports {
+		for _, report := range reports {
+			fmt.Fprintf(writer, "[%s] CWE-%d %s\n", vulnID, report.CWE, report.Description)
+			if report.Evidence != nil && report.Evidence.Message != "" {
+				fmt.Fprintf(writer, "\t%s\n", report.Evidence.Message)
+			}
+		}
+	}
 }
@@ -1,6 +1,7 @@
 package scanner
 
 import (
+	"net/http"
 	"net/url"
 	"strings"
 
@@ -9,90 +10,90 @@ import (
 )
 
 // ScopeService is used to ensure we stay with in the scope
-// of the target as we scan
-// TODO: make this better (support for schemes/params etc)
+// of the target as we scan, see scopeRule for the rule syntax
 type ScopeService struct {
-	target       *url.URL
-	allowed      []string
-	ignored      []string
-	excluded     []string
-	excludedURIs []string // todo make regex
+	target        *url.URL
+	allowed       []*scopeRule
+	ignored       []*scopeRule
+	excluded      []*scopeRule
+	excludedURIs  []*scopeRule
+	excludedForms []*formRule
 }
 
-// NewScopeService set the target url for easier matching
-// TODO: allow ports as well
+// NewScopeService set the target url for easier matching, if the target has
+// an explicit port only that port is in scope
 func NewScopeService(target *url.URL) *ScopeService {
 	s := &ScopeService{
-		target:       target,
-		allowed:      make([]string, 0),
-		ignored:      make([]string, 0),
-		excluded:     make([]string, 0),
-		excludedURIs: make([]string, 0),
+		target:        target,
+		allowed:       make([]*scopeRule, 0),
+		ignored:       make([]*scopeRule, 0),
+		excluded:      make([]*scopeRule, 0),
+		excludedURIs:  make([]*scopeRule, 0),
+		excludedForms: make([]*formRule, 0),
 	}
-	s.AddScope([]string{target.Hostname()}, browserk.InScope)
+	s.AddScope([]string{target.Host}, browserk.InScope)
 	return s
 }
 
-// AddScope to the scope service
+// AddScope rules to the scope service
 func (s *ScopeService) AddScope(inputs []string, scope browserk.Scope) {
+	for _, input := range inputs {
+		rule, err := parseHostRule(input)
+		if err != nil {
+			log.Warn().Err(err).Str("rule", input).Msg("failed to add scope rule")
+			continue
+		}
 
-	if inputs == nil || len(inputs) == 0 {
-		return
-	}
-	lowered := mapFunction(inputs, strings.ToLower)
-
-	switch scope {
-	case browserk.InScope:
-		s.allowed = append(s.allowed, lowered...)
-	case browserk.OutOfScope:
-		s.ignored = append(s.ignored, lowered...)
-	case browserk.ExcludedFromScope:
-		s.excluded = append(s.excluded, lowered...)
+		switch scope {
+		case browserk.InScope:
+			s.allowed = append(s.allowed, rule)
+		case browserk.OutOfScope:
+			s.ignored = append(s.ignored, rule)
+		case browserk.ExcludedFromScope:
+			s.excluded = append(s.excluded, rule)
+		}
 	}
 }
 
-// AddExcludedURIs so we don't logout or whatever
-// TODO: allow ability to add query params as well
+// AddExcludedURIs so we don't logout or whatever, inputs without a scheme are
+// paths (or path globs) and apply to every host. Query params are matched with
+// ?name[=glob] pairs separated by &, every param must be in the url to match
+// (/admin?action=delete, /admin?token)
 func (s *ScopeService) AddExcludedURIs(inputs []string) {
 	for _, input := range inputs {
-		if strings.HasPrefix(input, "http") {
-			u, err := url.Parse(input)
-			if err != nil {
-				log.Warn().Err(err).Msg("failed to add URI to exclusion list")
-				continue
-			}
-			s.excludedURIs = append(s.excludedURIs, strings.ToLower(u.Path))
-		} else {
-			s.excludedURIs = append(s.excludedURIs, strings.ToLower(input))
+		rule, err := parsePathRule(input)
+		if err != nil {
+			log.Warn().Err(err).Str("rule", input).Msg("failed to add URI to exclusion list")
+			continue
 		}
+		s.excludedURIs = append(s.excludedURIs, rule)
 	}
 }
 
-// Check a url to see if it's in scope
+// Check a url to see if it's in scope for a GET request, relative urls are resolved against the target
 func (s *ScopeService) Check(uri string) browserk.Scope {
+	return s.CheckRequest(http.MethodGet, uri)
+}
+
+// CheckRequest checks the method and url to see if it's in scope, relative urls are resolved against the target
+func (s *ScopeService) CheckRequest(method, uri string) browserk.Scope {
 	lowered := strings.ToLower(uri)
-	host := s.target.Hostname()
 
-	if strings.HasPrefix(lowered, "http") {
-		u, err := url.Parse(lowered)
-		if err != nil {
-			log.Warn().Err(err).Str("uri", lowered).Msg("failed to parse URI returning out of scope")
-			return browserk.OutOfScope
-		}
-		host = u.Hostname()
-		lowered = u.Path
-	} else if strings.HasPrefix(lowered, "//") {
-		u, err := url.Parse("http:" + lowered)
-		if err != nil {
-			log.Warn().Err(err).Str("uri", lowered).Msg("failed to parse URI returning out of scope")
-			return browserk.OutOfScope
+	if strings.HasPrefix(lowered, "//") {
+		lowered = s.target.Scheme + ":" + lowered
+	} else if !strings.HasPrefix(lowered, "http") {
+		if !strings.HasPrefix(lowered, "/") {
+			lowered = "/" + lowered
 		}
-		host = u.Hostname()
-		lowered = u.Path
-	} else if !strings.HasPrefix(lowered, "/") {
-		lowered = "/" + lowered
+		lowered = s.target.Scheme + "://" + s.target.Host + lowered
+	}
+
+	u, err := url.Parse(lowered)
+	if err != nil {
+		log.Warn().Err(err).Str("uri", lowered).Msg("failed to parse URI returning out of scope")
+		return browserk.OutOfScope
 	}
-	return s.CheckRelative(host, lowered)
+	return s.checkURL(method, u)
 }
 
 // ResolveBaseHref for html document links
@@ -111,46 +112,76 @@ func (s *ScopeService) ResolveBaseHref(baseHref, candidate string) browserk.Scop
 	return scope
 }
 
-// CheckRelative hosts to see if it's in scope
-// First we check if excluded, then we check if it's ignored,
-// then we check if the uri is excluded and finally if it's allowed
-// default to out of scope
+// CheckRelative hosts (host or host:port) to see if it's in scope using the target's scheme
 func (s *ScopeService) CheckRelative(host, relative string) browserk.Scope {
-	if includeFunction(s.excluded, host) {
-		return browserk.ExcludedFromScope
-	} else if includeFunction(s.ignored, host) {
+	u, err := url.Parse(relative)
+	if err != nil {
+		log.Warn().Err(err).Str("uri", relative).Msg("failed to parse URI returning out of scope")
 		return browserk.OutOfScope
-	} else if includeFunction(s.excludedURIs, relative) {
+	}
+	u.Scheme = s.target.Scheme
+	u.Host = host
+	return s.checkURL(http.MethodGet, u)
+}
+
+// checkURL against the rules in order of precedence:
+// excluded hosts, excluded uris, ignored hosts and finally allowed hosts,
+// anything else defaults to out of scope
+func (s *ScopeService) checkURL(method string, u *url.URL) browserk.Scope {
+	if matchRules(s.excluded, method, u) {
 		return browserk.ExcludedFromScope
-	} else if includeFunction(s.allowed, host) {
+	} else if matchRules(s.excludedURIs, method, u) {
+		return browserk.ExcludedFromScope
+	} else if matchRules(s.ignored, method, u) {
+		return browserk.OutOfScope
+	} else if matchRules(s.allowed, method, u) {
 		return browserk.InScope
 	}
 	return browserk.OutOfScope
 }
 
-// ExcludeForms based on name or id for html element
-func (s *ScopeService) ExcludeForms(idsOrNames []string) {
-	// TODO IMPLEMENT
+// ExcludeForms by id or name, action:<uri rule> for the form's action or field:<glob>
+// for forms containing a field with a matching name, id, value or text
+func (s *ScopeService) ExcludeForms(rules []string) {
+	for _, input := range rules {
+		rule, err := parseFormRule(input)
+		if err != nil {
+			log.Warn().Err(err).Str("rule", input).Msg("failed to add form to exclusion list")
+			continue
+		}
+		s.excludedForms = append(s.excludedForms, rule)
+	}
 }
 
-func mapFunction(vs []string, f func(string) string) []string {
-	vsm := make([]string, len(vs))
-	for i, v := range vs {
-		vsm[i] = f(v)
+// CheckForm returns ExcludedFromScope if the form matches an excluded form rule
+// otherwise the scope of submitting the form to its action
+func (s *ScopeService) CheckForm(baseHref string, form *browserk.HTMLFormElement) browserk.Scope {
+	method := strings.ToUpper(form.GetAttribute("method"))
+	if method == "" {
+		method = http.MethodGet
 	}
-	return vsm
-}
 
-func indexFunction(vs []string, t string) int {
-	for i, v := range vs {
-		if v == t {
-			return i
+	actionURL := s.resolve(baseHref, form.GetAttribute("action"))
+	for _, rule := range s.excludedForms {
+		if rule.match(form, method, actionURL) {
+			return browserk.ExcludedFromScope
 		}
 	}
-	return -1
+	return s.checkURL(method, actionURL)
 }
 
-// TODO: do regex matching
-func includeFunction(vs []string, t string) bool {
-	return indexFunction(vs, t) >= 0
+// resolve the candidate against the base href (or target) to an absolute url
+func (s *ScopeService) resolve(baseHref, candidate string) *url.URL {
+	base := s.target
+	if strings.HasPrefix(baseHref, "http") {
+		if u, err := url.Parse(baseHref); err == nil {
+			base = u
+		}
+	}
+
+	ref, err := url.Parse(strings.TrimSpace(candidate))
+	if err != nil {
+		return base
+	}
+	return base.ResolveReference(ref)
 }
@@ -0,0 +1,370 @@
+package scanner
+
+import (
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gitlab.com/browserker/browserk"
+)
+
+// regexRulePrefix marks a rule as a regular expression matched against the full url
+const regexRulePrefix = "re:"
+
+// scopeRule matches requests by method, scheme, host, port, path and query, empty parts match anything.
+// Rules are written as:
+//
+//	example.com                  host on any scheme and port
+//	*.example.com                any subdomain of example.com
+//	example.com:8080             host and port
+//	https://example.com          scheme and host
+//	https://*.example.com/api/*  scheme, host and path glob
+//	/admin/*                     path glob on any host
+//	/admin?action=delete         path with a query param value (glob)
+//	/admin?token                 path with a query param of any value
+//	api?.example.com/v?/users    ? matches a single character in hosts and paths
+//	POST /account/*              method and path glob
+//	re:^https?://[^/]+/v[0-9]/   regex against the full url (case insensitive)
+//
+// Host and path globs support * (any characters) and ? (a single character). A ? in the path
+// that is followed by a param name starts the query, query params require a path (use /*?token
+// for any path). Every query param of a rule must be present in the url to match.
+type scopeRule struct {
+	input  string
+	method string
+	scheme string
+	host   *regexp.Regexp
+	port   string
+	path   *regexp.Regexp
+	query  []*queryRule
+	re     *regexp.Regexp
+}
+
+// queryRule matches a query param by name and optionally its value
+type queryRule struct {
+	name  string
+	value *regexp.Regexp
+}
+
+// parseHostRule parses a rule where a value without a scheme is a host
+func parseHostRule(input string) (*scopeRule, error) {
+	return parseScopeRule(input, false)
+}
+
+// parsePathRule parses a rule where a value without a scheme is a path
+func parsePathRule(input string) (*scopeRule, error) {
+	return parseScopeRule(input, true)
+}
+
+func parseScopeRule(input string, pathOnly bool) (*scopeRule, error) {
+	rule := &scopeRule{input: input}
+	trimmed := strings.TrimSpace(input)
+	if trimmed == "" {
+		return nil, errors.New("empty scope rule")
+	}
+
+	if idx := strings.IndexByte(trimmed, ' '); idx != -1 && isMethod(trimmed[:idx]) {
+		rule.method = strings.ToUpper(trimmed[:idx])
+		trimmed = strings.TrimSpace(trimmed[idx+1:])
+	}
+
+	if strings.HasPrefix(trimmed, regexRulePrefix) {
+		re, err := regexp.Compile("(?i)" + strings.TrimPrefix(trimmed, regexRulePrefix))
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid scope regex")
+		}
+		rule.re = re
+		return rule, nil
+	}
+
+	lowered := strings.ToLower(trimmed)
+	if idx := queryIndex(lowered, pathOnly); idx != -1 {
+		query, err := parseQueryRules(lowered[idx+1:])
+		if err != nil {
+			return nil, err
+		}
+		rule.query = query
+		lowered = lowered[:idx]
+	}
+
+	if idx := strings.Index(lowered, "://"); idx != -1 {
+		rule.scheme = lowered[:idx]
+		lowered = lowered[idx+3:]
+	} else if pathOnly {
+		if !strings.HasPrefix(lowered, "/") {
+			lowered = "/" + lowered
+		}
+		return rule, rule.setPath(lowered)
+	}
+
+	hostPort := lowered
+	if idx := strings.Index(lowered, "/"); idx != -1 {
+		hostPort = lowered[:idx]
+		if err := rule.setPath(lowered[idx:]); err != nil {
+			return nil, err
+		}
+	}
+
+	if host, port, err := net.SplitHostPort(hostPort); err == nil {
+		hostPort = host
+		rule.port = port
+	}
+
+	if hostPort != "" && hostPort != "*" {
+		host, err := globToRegexp(hostPort)
+		if err != nil {
+			return nil, err
+		}
+		rule.host = host
+	}
+	return rule, nil
+}
+
+// queryIndex returns the index of the ? that starts the query or -1. Only a ? in the path that
+// is followed by a param name counts, any other ? is a single character glob
+func queryIndex(rule string, pathOnly bool) int {
+	pathStart := 0
+	if idx := strings.Index(rule, "://"); idx != -1 {
+		pathStart = idx + 3
+		pathOnly = false
+	}
+
+	if !pathOnly {
+		idx := strings.IndexByte(rule[pathStart:], '/')
+		if idx == -1 {
+			return -1
+		}
+		pathStart += idx
+	}
+
+	for i := pathStart; i < len(rule); i++ {
+		if rule[i] != '?' {
+			continue
+		}
+
+		name := rule[i+1:]
+		if end := strings.IndexAny(name, "=&"); end != -1 {
+			name = name[:end]
+		}
+		if name != "" && !strings.ContainsAny(name, "/*?") {
+			return i
+		}
+	}
+	return -1
+}
+
+// parseQueryRules of name[=value glob] pairs separated by &
+func parseQueryRules(query string) ([]*queryRule, error) {
+	rules := make([]*queryRule, 0)
+	for _, param := range strings.Split(query, "&") {
+		if param == "" {
+			continue
+		}
+
+		name, value := param, ""
+		if idx := strings.IndexByte(param, '='); idx != -1 {
+			name, value = param[:idx], param[idx+1:]
+		}
+
+		rule := &queryRule{name: name}
+		if value != "" && value != "*" {
+			re, err := globToRegexp(value)
+			if err != nil {
+				return nil, err
+			}
+			rule.value = re
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// isMethod returns true if the token looks like an http method
+func isMethod(token string) bool {
+	for _, c := range token {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return token != ""
+}
+
+func (r *scopeRule) setPath(path string) error {
+	if path == "" || path == "/*" {
+		return nil
+	}
+	re, err := globToRegexp(path)
+	if err != nil {
+		return err
+	}
+	r.path = re
+	return nil
+}
+
+// match the request against all parts of the rule, an empty method matches any rule
+func (r *scopeRule) match(method string, u *url.URL) bool {
+	if r.method != "" && method != "" && !strings.EqualFold(r.method, method) {
+		return false
+	}
+
+	if r.re != nil {
+		return r.re.MatchString(u.String())
+	}
+
+	if r.scheme != "" && r.scheme != strings.ToLower(u.Scheme) {
+		return false
+	}
+
+	if r.host != nil && !r.host.MatchString(strings.ToLower(u.Hostname())) {
+		return false
+	}
+
+	if r.port != "" && r.port != effectivePort(u) {
+		return false
+	}
+
+	if r.path != nil {
+		path := strings.ToLower(u.Path)
+		if path == "" {
+			path = "/"
+		}
+		if !r.path.MatchString(path) {
+			return false
+		}
+	}
+	return r.matchQuery(u)
+}
+
+// matchQuery returns true if every query rule matches a param of the url
+func (r *scopeRule) matchQuery(u *url.URL) bool {
+	if len(r.query) == 0 {
+		return true
+	}
+
+	params := make(map[string][]string)
+	for name, values := range u.Query() {
+		lowered := strings.ToLower(name)
+		params[lowered] = append(params[lowered], values...)
+	}
+
+	for _, rule := range r.query {
+		values, ok := params[rule.name]
+		if !ok {
+			return false
+		}
+		if rule.value == nil {
+			continue
+		}
+
+		matched := false
+		for _, value := range values {
+			if rule.value.MatchString(strings.ToLower(value)) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// effectivePort of the url, using the scheme's default port if none was given
+func effectivePort(u *url.URL) string {
+	if port := u.Port(); port != "" {
+		return port
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case "https", "wss":
+		return "443"
+	case "http", "ws":
+		return "80"
+	}
+	return ""
+}
+
+// globToRegexp converts a glob where * is any number of characters and ? a single
+// character to an anchored regex
+func globToRegexp(glob string) (*regexp.Regexp, error) {
+	var sb strings.Builder
+	sb.WriteString("^")
+	for _, c := range glob {
+		switch c {
+		case '*':
+			sb.WriteString(".*")
+		case '?':
+			sb.WriteString(".")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	sb.WriteString("$")
+	return regexp.Compile(sb.String())
+}
+
+// formRule prefixes
+const (
+	formActionPrefix = "action:"
+	formFieldPrefix  = "field:"
+)
+
+// formRule excludes forms by id/name, action url or a contained field
+type formRule struct {
+	idOrName string
+	action   *scopeRule
+	field    *regexp.Regexp
+}
+
+func parseFormRule(input string) (*formRule, error) {
+	trimmed := strings.TrimSpace(input)
+	lowered := strings.ToLower(trimmed)
+	switch {
+	case trimmed == "":
+		return nil, errors.New("empty form rule")
+	case strings.HasPrefix(lowered, formActionPrefix):
+		action, err := parsePathRule(trimmed[len(formActionPrefix):])
+		if err != nil {
+			return nil, err
+		}
+		return &formRule{action: action}, nil
+	case strings.HasPrefix(lowered, formFieldPrefix):
+		field, err := globToRegexp(lowered[len(formFieldPrefix):])
+		if err != nil {
+			return nil, err
+		}
+		return &formRule{field: field}, nil
+	}
+	return &formRule{idOrName: lowered}, nil
+}
+
+// match the form by its id/name, resolved action url or child elements
+func (r *formRule) match(form *browserk.HTMLFormElement, method string, action *url.URL) bool {
+	switch {
+	case r.action != nil:
+		return r.action.match(method, action)
+	case r.field != nil:
+		for _, child := range form.ChildElements {
+			for _, value := range []string{child.GetAttribute("name"), child.GetAttribute("id"), child.GetAttribute("value"), child.InnerText} {
+				if value != "" && r.field.MatchString(strings.ToLower(strings.TrimSpace(value))) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	return strings.ToLower(form.GetAttribute("id")) == r.idOrName || strings.ToLower(form.GetAttribute("name")) == r.idOrName
+}
+
+// matchRules returns true if any of the rules match the request
+func matchRules(rules []*scopeRule, method string, u *url.URL) bool {
+	for _, rule := range rules {
+		if rule.match(method, u) {
+			return true
+		}
+	}
+	return false
+}
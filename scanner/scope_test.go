@@ -1,11 +1,14 @@
 package scanner_test
 
 import (
+	"context"
 	"net/url"
 	"strings"
 	"testing"
 
+	"github.com/wirepair/gcd/gcdapi"
 	"gitlab.com/browserker/browserk"
+	"gitlab.com/browserker/mock"
 	"gitlab.com/browserker/scanner"
 )
 
@@ -56,7 +59,7 @@ func TestScope(t *testing.T) {
 		},
 		{
 			"http://bad.com/signout",
-			browserk.OutOfScope,
+			browserk.ExcludedFromScope,
 		},
 		{
 			"statistics.php",
@@ -105,3 +108,178 @@ func TestScope(t *testing.T) {
 
 	}
 }
+
+func TestScopeRules(t *testing.T) {
+	target, _ := url.Parse("https://app.example.com/")
+	s := scanner.NewScopeService(target)
+	s.AddScope([]string{"*.example.com", "http://legacy.example.org:8080", "re:^https://api[0-9]+\\.example\\.net/"}, browserk.InScope)
+	s.AddScope([]string{"cdn.example.com", "https://*.example.com/static/*"}, browserk.OutOfScope)
+	s.AddScope([]string{"admin.example.com:8443", "*.internal.example.com"}, browserk.ExcludedFromScope)
+	s.AddExcludedURIs([]string{"/logout", "/account/*/delete", "/api/v*/reset*", "re:[?&]action=logout", "https://app.example.com/exact"})
+
+	var tests = []struct {
+		name     string
+		in       string
+		expected browserk.Scope
+	}{
+		{"target", "https://app.example.com/", browserk.InScope},
+		{"relative", "/profile", browserk.InScope},
+		{"subdomain wildcard", "https://shop.example.com/cart", browserk.InScope},
+		{"nested subdomain wildcard", "https://a.b.example.com/", browserk.InScope},
+		{"apex not matched by wildcard", "https://example.com/", browserk.OutOfScope},
+		{"other domain", "https://example.org/", browserk.OutOfScope},
+		{"scheme and port", "http://legacy.example.org:8080/index.php", browserk.InScope},
+		{"wrong scheme", "https://legacy.example.org:8080/index.php", browserk.OutOfScope},
+		{"wrong port", "http://legacy.example.org/index.php", browserk.OutOfScope},
+		{"regex host", "https://api12.example.net/users", browserk.InScope},
+		{"regex host wrong scheme", "http://api12.example.net/users", browserk.OutOfScope},
+		{"ignored host", "https://cdn.example.com/app.js", browserk.OutOfScope},
+		{"ignored path glob", "https://shop.example.com/static/css/site.css", browserk.OutOfScope},
+		{"ignored path glob other scheme", "http://shop.example.com/static/css/site.css", browserk.InScope},
+		{"excluded host port", "https://admin.example.com:8443/", browserk.ExcludedFromScope},
+		{"excluded host other port", "https://admin.example.com/", browserk.InScope},
+		{"excluded wildcard", "https://db.internal.example.com/", browserk.ExcludedFromScope},
+		{"excluded host before ignored path", "https://db.internal.example.com/static/x.js", browserk.ExcludedFromScope},
+		{"excluded uri", "https://shop.example.com/logout", browserk.ExcludedFromScope},
+		{"excluded uri relative", "logout", browserk.ExcludedFromScope},
+		{"excluded uri prefix only", "https://app.example.com/logout/now", browserk.InScope},
+		{"excluded uri glob", "https://app.example.com/account/12/delete", browserk.ExcludedFromScope},
+		{"excluded uri multiple globs", "https://app.example.com/api/v2/reset-password", browserk.ExcludedFromScope},
+		{"excluded uri multiple globs no match", "https://app.example.com/api/v2/users", browserk.InScope},
+		{"excluded uri regex", "https://app.example.com/do?action=logout", browserk.ExcludedFromScope},
+		{"excluded absolute uri", "https://app.example.com/exact", browserk.ExcludedFromScope},
+		{"excluded absolute uri other host", "https://shop.example.com/exact", browserk.InScope},
+		{"excluded uri before ignored host", "https://cdn.example.com/logout", browserk.ExcludedFromScope},
+		{"case insensitive", "HTTPS://SHOP.EXAMPLE.COM/LOGOUT", browserk.ExcludedFromScope},
+	}
+
+	for _, tt := range tests {
+		if ret := s.Check(tt.in); ret != tt.expected {
+			t.Fatalf("%s: %v did not match %v for %s\n", tt.name, ret, tt.expected, tt.in)
+		}
+	}
+
+	// explicit target ports restrict the default scope to that port
+	target, _ = url.Parse("http://localhost:8080/")
+	s = scanner.NewScopeService(target)
+	if ret := s.Check("http://localhost:9090/"); ret != browserk.OutOfScope {
+		t.Fatalf("expected other port to be out of scope got %v\n", ret)
+	}
+	if ret := s.Check("/index.php"); ret != browserk.InScope {
+		t.Fatalf("expected relative url to be in scope got %v\n", ret)
+	}
+}
+
+func TestScopeForms(t *testing.T) {
+	target, _ := url.Parse("http://example.com/")
+	s := scanner.NewScopeService(target)
+	s.AddExcludedURIs([]string{"/logout"})
+	s.ExcludeForms([]string{"SearchForm", "action:/admin/*", "field:*delete*"})
+
+	form := func(id, action string, children ...*browserk.HTMLElement) *browserk.HTMLFormElement {
+		return &browserk.HTMLFormElement{Attributes: map[string]string{"id": id, "action": action}, ChildElements: children}
+	}
+
+	var tests = []struct {
+		name     string
+		baseHref string
+		form     *browserk.HTMLFormElement
+		expected browserk.Scope
+	}{
+		{"in scope", "", form("profile", "/profile", mock.MakeMockButton("submit", "Save")), browserk.InScope},
+		{"id", "", form("searchform", "/search"), browserk.ExcludedFromScope},
+		{"name", "", &browserk.HTMLFormElement{Attributes: map[string]string{"name": "searchForm", "action": "/search"}}, browserk.ExcludedFromScope},
+		{"action glob", "", form("users", "/admin/users/1"), browserk.ExcludedFromScope},
+		{"relative action glob", "http://example.com/admin/", form("users", "users/1"), browserk.ExcludedFromScope},
+		{"empty action uses base", "http://example.com/admin/users", form("users", ""), browserk.ExcludedFromScope},
+		{"field text", "", form("account", "/account", mock.MakeMockButton("submit", "Delete Account")), browserk.ExcludedFromScope},
+		{"field name", "", form("account", "/account", mock.MakeMockInput("checkbox", "delete_all", "")), browserk.ExcludedFromScope},
+		{"excluded uri action", "", form("bye", "/logout"), browserk.ExcludedFromScope},
+		{"out of scope action", "", form("other", "http://other.com/"), browserk.OutOfScope},
+	}
+
+	for _, tt := range tests {
+		if ret := s.CheckForm(tt.baseHref, tt.form); ret != tt.expected {
+			t.Fatalf("%s: %v did not match %v\n", tt.name, ret, tt.expected)
+		}
+	}
+}
+
+func TestScopeRequestRules(t *testing.T) {
+	target, _ := url.Parse("http://example.com/")
+	s := scanner.NewScopeService(target)
+	s.AddScope([]string{"*.example.com"}, browserk.InScope)
+	s.AddExcludedURIs([]string{"/admin?action=delete", "/users?id&confirm=y*", "/v?/users", "/v?/items?sort=*", "https://api?.example.com/*?debug", "POST /account/*", "DELETE *", "GET re:/export\\?format=csv"})
+
+	var tests = []struct {
+		name     string
+		method   string
+		in       string
+		expected browserk.Scope
+	}{
+		{"path without param", "GET", "/admin", browserk.InScope},
+		{"param value", "GET", "/admin?action=delete", browserk.ExcludedFromScope},
+		{"param value among others", "GET", "http://example.com/admin?page=2&action=Delete", browserk.ExcludedFromScope},
+		{"other param value", "GET", "/admin?action=view", browserk.InScope},
+		{"all params required", "GET", "/users?id=1", browserk.InScope},
+		{"all params present", "GET", "/users?confirm=yes&id=1", browserk.ExcludedFromScope},
+		{"param value glob no match", "GET", "/users?confirm=no&id=1", browserk.InScope},
+		{"single character path glob", "GET", "/v1/users", browserk.ExcludedFromScope},
+		{"single character path glob no match", "GET", "/v10/users", browserk.InScope},
+		{"path glob and param", "GET", "/v2/items?sort=name", browserk.ExcludedFromScope},
+		{"path glob without param", "GET", "/v2/items", browserk.InScope},
+		{"single character host glob and param", "GET", "https://api2.example.com/status?debug=1", browserk.ExcludedFromScope},
+		{"single character host glob without param", "GET", "https://api2.example.com/status", browserk.InScope},
+		{"method", "POST", "/account/close", browserk.ExcludedFromScope},
+		{"other method", "GET", "/account/close", browserk.InScope},
+		{"method any path", "DELETE", "/anything", browserk.ExcludedFromScope},
+		{"method regex", "GET", "/export?format=csv", browserk.ExcludedFromScope},
+		{"method regex other method", "POST", "/export?format=csv", browserk.InScope},
+	}
+
+	for _, tt := range tests {
+		if ret := s.CheckRequest(tt.method, tt.in); ret != tt.expected {
+			t.Fatalf("%s: %v did not match %v for %s %s\n", tt.name, ret, tt.expected, tt.method, tt.in)
+		}
+	}
+
+	// forms are checked with their method
+	form := &browserk.HTMLFormElement{Attributes: map[string]string{"action": "/account/close", "method": "post"}}
+	if ret := s.CheckForm("", form); ret != browserk.ExcludedFromScope {
+		t.Fatalf("expected post form to be excluded got %v\n", ret)
+	}
+	form.Attributes["method"] = "get"
+	if ret := s.CheckForm("", form); ret != browserk.InScope {
+		t.Fatalf("expected get form to be in scope got %v\n", ret)
+	}
+}
+
+func TestBlockExcludedRequests(t *testing.T) {
+	target, _ := url.Parse("http://example.com/")
+	s := scanner.NewScopeService(target)
+	s.AddScope([]string{"tracker.com"}, browserk.ExcludedFromScope)
+	s.AddExcludedURIs([]string{"/admin?action=delete"})
+
+	ctx := mock.Context(context.Background())
+	ctx.Scope = s
+
+	var tests = []struct {
+		in       string
+		expected string
+	}{
+		{"http://example.com/admin", ""},
+		{"http://example.com/admin?action=delete", browserk.BlockedByClient},
+		{"https://tracker.com/pixel.gif", browserk.BlockedByClient},
+	}
+
+	for _, tt := range tests {
+		req := &browserk.InterceptedHTTPRequest{
+			Request:  &gcdapi.NetworkRequest{Url: tt.in, Method: "GET"},
+			Modified: &browserk.HTTPModifiedRequest{},
+		}
+		browserk.BlockExcludedRequests(ctx, nil, req)
+		if req.Modified.FailReason != tt.expected {
+			t.Fatalf("expected %s to have fail reason %s got %s\n", tt.in, tt.expected, req.Modified.FailReason)
+		}
+	}
+}
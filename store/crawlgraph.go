@@ -5,10 +5,12 @@ import (
 	"context"
 	"os"
 	"reflect"
+	"time"
 
 	badger "github.com/dgraph-io/badger/v2"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
+	"github.com/vmihailenco/msgpack/v4"
 	"gitlab.com/browserker/browserk"
 )
 
@@ -22,6 +24,7 @@ type CrawlGraph struct {
 	filepath            string
 	navPredicates       []*NavGraphField
 	navResultPredicates []*NavGraphField
+	maxDepth            int // navigations with a greater distance are not added or returned (0 no limit)
 }
 
 // NewCrawlGraph creates a new crawl graph and request store
@@ -29,6 +32,16 @@ func NewCrawlGraph(filepath string) *CrawlGraph {
 	return &CrawlGraph{filepath: filepath}
 }
 
+// SetMaxDepth (to be called before adding navigations) of navigations we add and find
+func (g *CrawlGraph) SetMaxDepth(depth int) {
+	g.maxDepth = depth
+}
+
+// tooDeep returns true if the navigation is beyond our max depth
+func (g *CrawlGraph) tooDeep(nav *browserk.Navigation) bool {
+	return g.maxDepth > 0 && nav.Distance > g.maxDepth
+}
+
 // Init the crawl graph and request store
 func (g *CrawlGraph) Init() error {
 	var err error
@@ -73,6 +86,10 @@ func (g *CrawlGraph) discoverPredicates(f interface{}) []*NavGraphField {
 
 // AddNavigation entry into our graph and requests into request store if it's unique
 func (g *CrawlGraph) AddNavigation(nav *browserk.Navigation) error {
+	if g.tooDeep(nav) {
+		log.Debug().Int("distance", nav.Distance).Msg("not adding nav as it exceeds max depth")
+		return nil
+	}
 
 	return g.GraphStore.Update(func(txn *badger.Txn) error {
 		existKey := MakeKey(nav.ID, "id")
@@ -106,6 +123,10 @@ func (g *CrawlGraph) AddNavigations(navs []*browserk.Navigation) error {
 
 	return g.GraphStore.Update(func(txn *badger.Txn) error {
 		for _, nav := range navs {
+			if g.tooDeep(nav) {
+				log.Debug().Int("distance", nav.Distance).Msg("not adding nav as it exceeds max depth")
+				continue
+			}
 
 			existKey := MakeKey(nav.ID, "id")
 			_, err := txn.Get(existKey)
@@ -196,22 +217,147 @@ func (g *CrawlGraph) AddResult(result *browserk.NavigationResult) error {
 			txn.Set(key, bytez)
 		}
 		// set the navigation id to visited
-		// TODO: track failures
 		navIDkey := MakeKey(result.NavigationID, "state")
 		value, _ := EncodeState(browserk.NavVisited)
 		return txn.Set(navIDkey, value)
 	})
 }
 
-// FailNavigation for this navID
-func (g *CrawlGraph) FailNavigation(navID []byte) error {
-	return g.GraphStore.Update(func(txn *badger.Txn) error {
-		// set the navigation id to visited
-		// TODO: track failures
-		navIDkey := MakeKey(navID, "state")
-		value, _ := EncodeState(browserk.NavFailed)
-		return txn.Set(navIDkey, value)
+// FailNavigation for this navID, recording the reason and error. The navigation is set to
+// NavRetry until it has failed more than maxRetries times, then it is set to NavFailed.
+// Returns the state the navigation was set to
+func (g *CrawlGraph) FailNavigation(navID []byte, reason browserk.FailureReason, navErr error, maxRetries int) (browserk.NavState, error) {
+	state := browserk.NavFailed
+	err := g.GraphStore.Update(func(txn *badger.Txn) error {
+		failures := 0
+		if item, err := txn.Get(MakeKey(navID, "failures")); err == nil {
+			err = item.Value(func(val []byte) error {
+				return msgpack.Unmarshal(val, &failures)
+			})
+			if err != nil {
+				return err
+			}
+		}
+		failures++
+
+		lastErr := ""
+		if navErr != nil {
+			lastErr = navErr.Error()
+		}
+
+		values := map[string]interface{}{
+			"failures":    failures,
+			"fail_reason": reason,
+			"last_err":    lastErr,
+		}
+		for pred, v := range values {
+			bytez, err := msgpack.Marshal(v)
+			if err != nil {
+				return err
+			}
+			if err := txn.Set(MakeKey(navID, pred), bytez); err != nil {
+				return err
+			}
+		}
+
+		if failures <= maxRetries {
+			state = browserk.NavRetry
+		}
+		return UpdateState(txn, state, [][]byte{navID})
+	})
+	return state, err
+}
+
+// RetryNavigations sets NavRetry navigations whose backoff (by number of failures) has passed
+// to unvisited. Returns how many were set to unvisited and how many are still waiting
+func (g *CrawlGraph) RetryNavigations(backoff func(failures int) time.Duration) (int, int) {
+	ready := 0
+	waiting := 0
+	err := g.GraphStore.Update(func(txn *badger.Txn) error {
+		nodeIDs, err := StateIterator(txn, browserk.NavRetry, -1)
+		if err != nil || nodeIDs == nil {
+			return err
+		}
+
+		readyIDs := make([][]byte, 0)
+		for _, nodeID := range nodeIDs {
+			nav, err := DecodeNavigation(txn, g.navPredicates, nodeID)
+			if err != nil {
+				return err
+			}
+
+			if time.Since(nav.StateUpdatedTime) < backoff(nav.Failures) {
+				waiting++
+				continue
+			}
+			readyIDs = append(readyIDs, nodeID)
+		}
+		ready = len(readyIDs)
+		return UpdateState(txn, browserk.NavUnvisited, readyIDs)
+	})
+
+	if err != nil {
+		log.Error().Err(err).Msg("failed to retry navigations")
+		return 0, waiting
+	}
+	return ready, waiting
+}
+
+// ResetInProcess sets every in process navigation back to unvisited (regardless of depth) so
+// a resumed crawl picks them up again, returning how many were reset
+func (g *CrawlGraph) ResetInProcess() int {
+	reset := 0
+	err := g.GraphStore.Update(func(txn *badger.Txn) error {
+		nodeIDs, err := StateIterator(txn, browserk.NavInProcess, -1)
+		if err != nil || nodeIDs == nil {
+			return err
+		}
+		reset = len(nodeIDs)
+		return UpdateState(txn, browserk.NavUnvisited, nodeIDs)
+	})
+
+	if err != nil {
+		log.Error().Err(err).Msg("failed to reset in process navigations")
+		return 0
+	}
+	return reset
+}
+
+// ResetStaleNavigations sets in process navigations whose state has not been updated within
+// the timeout back to unvisited, returning the ids of the navigations that were reset
+func (g *CrawlGraph) ResetStaleNavigations(timeout time.Duration) [][]byte {
+	var reset [][]byte
+	err := g.GraphStore.Update(func(txn *badger.Txn) error {
+		nodeIDs, err := StateIterator(txn, browserk.NavInProcess, -1)
+		if err != nil || nodeIDs == nil {
+			return err
+		}
+
+		staleIDs := make([][]byte, 0)
+		for _, nodeID := range nodeIDs {
+			item, err := txn.Get(MakeKey(nodeID, "state_updated"))
+			if err != nil {
+				return err
+			}
+
+			nav := &browserk.Navigation{}
+			if err := DecodeNavigationItem(item, nav, "state_updated"); err != nil {
+				return err
+			}
+
+			if time.Since(nav.StateUpdatedTime) > timeout {
+				staleIDs = append(staleIDs, nodeID)
+			}
+		}
+		reset = staleIDs
+		return UpdateState(txn, browserk.NavUnvisited, staleIDs)
 	})
+
+	if err != nil {
+		log.Error().Err(err).Msg("failed to reset stale navigations")
+		return nil
+	}
+	return reset
 }
 
 // GetNavigationResult from the navigation id
@@ -292,6 +438,7 @@ func (g *CrawlGraph) Find(ctx context.Context, byState, setState browserk.NavSta
 			}
 			log.Info().Msgf("Found new nodeIDs for nav, getting paths: %#v", nodeIDs)
 			entries, err = PathToNavIDs(txn, g.navPredicates, nodeIDs)
+			entries, _ = g.filterDepth(entries)
 			return err
 		})
 
@@ -316,7 +463,17 @@ func (g *CrawlGraph) Find(ctx context.Context, byState, setState browserk.NavSta
 				return err
 			}
 			entries, err = PathToNavIDs(txn, g.navPredicates, nodeIDs)
-			return errors.Wrap(err, "path to navs")
+			if err != nil {
+				return errors.Wrap(err, "path to navs")
+			}
+
+			// navs added before the max depth was lowered are skipped so we never pick them up again
+			var deepIDs [][]byte
+			entries, deepIDs = g.filterDepth(entries)
+			if len(deepIDs) > 0 {
+				return UpdateState(txn, browserk.NavSkipped, deepIDs)
+			}
+			return nil
 		})
 
 		// TODO: retry on transaction conflict errors
@@ -327,6 +484,29 @@ func (g *CrawlGraph) Find(ctx context.Context, byState, setState browserk.NavSta
 	return entries
 }
 
+// filterDepth removes the paths that end beyond our max depth, returning the ids of their final navs
+func (g *CrawlGraph) filterDepth(entries [][]*browserk.Navigation) ([][]*browserk.Navigation, [][]byte) {
+	if g.maxDepth <= 0 {
+		return entries, nil
+	}
+
+	filtered := make([][]*browserk.Navigation, 0, len(entries))
+	deepIDs := make([][]byte, 0)
+	for _, path := range entries {
+		if len(path) == 0 {
+			continue
+		}
+
+		last := path[len(path)-1]
+		if g.tooDeep(last) {
+			deepIDs = append(deepIDs, last.ID)
+			continue
+		}
+		filtered = append(filtered, path)
+	}
+	return filtered, deepIDs
+}
+
 // Close the graph store
 func (g *CrawlGraph) Close() error {
 	return g.GraphStore.Close()
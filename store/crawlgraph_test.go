@@ -1,8 +1,11 @@
 package store_test
 
 import (
+	"bytes"
+	"errors"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/davecgh/go-spew/spew"
 	"gitlab.com/browserker/browserk"
@@ -159,6 +162,244 @@ func TestCrawlAddNavigations(t *testing.T) {
 	testGetNavResults(t, g)
 }
 
+func TestCrawlMaxDepth(t *testing.T) {
+	path := "testdata/depth/crawl"
+	os.RemoveAll(path)
+
+	g := store.NewCrawlGraph(path)
+	if err := g.Init(); err != nil {
+		t.Fatalf("error init graph: %s\n", err)
+	}
+	defer g.Close()
+
+	navs := make([]*browserk.Navigation, 0)
+	for i := 1; i < 11; i++ {
+		nav := mock.MakeMockNavi([]byte{0, byte(i), 2})
+		nav.OriginID = []byte{0, byte(i - 1), 2}
+		nav.Distance = i - 1
+
+		if i == 1 {
+			nav.OriginID = []byte{} // signals root
+		}
+		navs = append(navs, nav)
+	}
+
+	// only distances 0-5 are added
+	g.SetMaxDepth(5)
+	if err := g.AddNavigations(navs); err != nil {
+		t.Fatalf("error calling add navigations: %s\n", err)
+	}
+
+	entries := g.Find(nil, browserk.NavUnvisited, browserk.NavUnvisited, 100)
+	if len(entries) != 6 {
+		t.Fatalf("expected 6 entries got %d\n", len(entries))
+	}
+
+	// lowering the depth skips navs that were already added
+	g.SetMaxDepth(2)
+	entries = g.Find(nil, browserk.NavUnvisited, browserk.NavInProcess, 100)
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries got %d\n", len(entries))
+	}
+
+	for _, entry := range entries {
+		if entry[len(entry)-1].Distance > 2 {
+			t.Fatalf("expected entries within max depth got %d\n", entry[len(entry)-1].Distance)
+		}
+	}
+
+	if entries = g.Find(nil, browserk.NavUnvisited, browserk.NavInProcess, 100); len(entries) != 0 {
+		t.Fatalf("expected deep navs to be skipped got %d entries\n", len(entries))
+	}
+
+	// skipped navs are beyond the max depth so remove it to find them
+	g.SetMaxDepth(0)
+	if entries = g.Find(nil, browserk.NavSkipped, browserk.NavSkipped, 100); len(entries) != 3 {
+		t.Fatalf("expected 3 skipped entries got %d\n", len(entries))
+	}
+}
+
+func TestCrawlResume(t *testing.T) {
+	path := "testdata/resume/crawl"
+	os.RemoveAll(path)
+
+	g := store.NewCrawlGraph(path)
+	if err := g.Init(); err != nil {
+		t.Fatalf("error init graph: %s\n", err)
+	}
+
+	navs := make([]*browserk.Navigation, 0)
+	for i := 1; i < 5; i++ {
+		nav := mock.MakeMockNavi([]byte{0, byte(i), 2})
+		nav.OriginID = []byte{} // signals root
+		navs = append(navs, nav)
+	}
+	navs[3].Distance = 5
+
+	if err := g.AddNavigations(navs); err != nil {
+		t.Fatalf("error calling add navigations: %s\n", err)
+	}
+
+	if entries := g.Find(nil, browserk.NavUnvisited, browserk.NavInProcess, 100); len(entries) != 4 {
+		t.Fatalf("expected 4 in process entries got %d\n", len(entries))
+	}
+
+	// only one nav completes before the crawl is interrupted
+	if err := g.AddResult(mock.MakeMockResult(navs[0].ID)); err != nil {
+		t.Fatalf("error adding result: %s\n", err)
+	}
+	g.Close()
+
+	g = store.NewCrawlGraph(path)
+	if err := g.Init(); err != nil {
+		t.Fatalf("error re-opening graph: %s\n", err)
+	}
+	defer g.Close()
+
+	// navs deeper than the max depth are reset too
+	g.SetMaxDepth(1)
+	if reset := g.ResetInProcess(); reset != 3 {
+		t.Fatalf("expected 3 in process entries to be reset got %d\n", reset)
+	}
+	g.SetMaxDepth(0)
+
+	if entries := g.Find(nil, browserk.NavInProcess, browserk.NavInProcess, 100); len(entries) != 0 {
+		t.Fatalf("expected no in process entries got %d\n", len(entries))
+	}
+
+	if entries := g.Find(nil, browserk.NavUnvisited, browserk.NavUnvisited, 100); len(entries) != 3 {
+		t.Fatalf("expected 3 unvisited entries got %d\n", len(entries))
+	}
+
+	if entries := g.Find(nil, browserk.NavVisited, browserk.NavVisited, 100); len(entries) != 1 {
+		t.Fatalf("expected visited entry to be kept got %d\n", len(entries))
+	}
+
+	result, err := g.GetNavigationResult(navs[0].ID)
+	if err != nil || result == nil || result.EndURL != "http://example.com/end" {
+		t.Fatalf("expected visited result to be kept: %v\n", err)
+	}
+}
+
+func TestCrawlFailNavigation(t *testing.T) {
+	path := "testdata/fail/crawl"
+	os.RemoveAll(path)
+
+	g := store.NewCrawlGraph(path)
+	if err := g.Init(); err != nil {
+		t.Fatalf("error init graph: %s\n", err)
+	}
+	defer g.Close()
+
+	nav := mock.MakeMockNavi([]byte{0, 1, 2})
+	nav.OriginID = []byte{}
+	if err := g.AddNavigation(nav); err != nil {
+		t.Fatalf("error adding: %s\n", err)
+	}
+
+	state, err := g.FailNavigation(nav.ID, browserk.FailTimeout, errors.New("navigation timed out"), 1)
+	if err != nil || state != browserk.NavRetry {
+		t.Fatalf("expected nav to be retried got state %d err: %v\n", state, err)
+	}
+
+	if ready, waiting := g.RetryNavigations(func(int) time.Duration { return time.Hour }); ready != 0 || waiting != 1 {
+		t.Fatalf("expected nav to wait for backoff got ready %d waiting %d\n", ready, waiting)
+	}
+
+	if ready, waiting := g.RetryNavigations(func(int) time.Duration { return 0 }); ready != 1 || waiting != 0 {
+		t.Fatalf("expected nav to be ready got ready %d waiting %d\n", ready, waiting)
+	}
+
+	if entries := g.Find(nil, browserk.NavUnvisited, browserk.NavInProcess, 100); len(entries) != 1 {
+		t.Fatalf("expected retried nav to be unvisited got %d entries\n", len(entries))
+	}
+
+	state, err = g.FailNavigation(nav.ID, browserk.FailElementNotFound, errors.New("element not found"), 1)
+	if err != nil || state != browserk.NavFailed {
+		t.Fatalf("expected nav to be failed got state %d err: %v\n", state, err)
+	}
+
+	failed, err := g.GetNavigation(nav.ID)
+	if err != nil {
+		t.Fatalf("error getting nav: %s\n", err)
+	}
+
+	if failed.Failures != 2 || failed.FailReason != browserk.FailElementNotFound || failed.LastError != "element not found" {
+		t.Fatalf("expected failure to be recorded got %d %d %s\n", failed.Failures, failed.FailReason, failed.LastError)
+	}
+
+	// every navigation waiting to be retried is checked, not just the first batch
+	for i := 0; i < 1100; i++ {
+		nav := mock.MakeMockNavi([]byte{1, byte(i >> 8), byte(i)})
+		nav.OriginID = []byte{}
+		if err := g.AddNavigation(nav); err != nil {
+			t.Fatalf("error adding: %s\n", err)
+		}
+
+		if _, err := g.FailNavigation(nav.ID, browserk.FailTimeout, errors.New("navigation timed out"), 1); err != nil {
+			t.Fatalf("error failing nav: %s\n", err)
+		}
+	}
+
+	if ready, waiting := g.RetryNavigations(func(int) time.Duration { return 0 }); ready != 1100 || waiting != 0 {
+		t.Fatalf("expected all navs to be ready got ready %d waiting %d\n", ready, waiting)
+	}
+}
+
+func TestCrawlResetStale(t *testing.T) {
+	path := "testdata/stale/crawl"
+	os.RemoveAll(path)
+
+	g := store.NewCrawlGraph(path)
+	if err := g.Init(); err != nil {
+		t.Fatalf("error init graph: %s\n", err)
+	}
+	defer g.Close()
+
+	nav := mock.MakeMockNavi([]byte{0, 1, 2})
+	nav.OriginID = []byte{}
+	if err := g.AddNavigation(nav); err != nil {
+		t.Fatalf("error adding: %s\n", err)
+	}
+
+	if entries := g.Find(nil, browserk.NavUnvisited, browserk.NavInProcess, 100); len(entries) != 1 {
+		t.Fatalf("expected 1 in process entry got %d\n", len(entries))
+	}
+
+	if reset := g.ResetStaleNavigations(time.Hour); len(reset) != 0 {
+		t.Fatalf("expected recently updated nav to not be reset got %d\n", len(reset))
+	}
+
+	time.Sleep(time.Millisecond * 10)
+	if reset := g.ResetStaleNavigations(time.Millisecond); len(reset) != 1 || !bytes.Equal(reset[0], nav.ID) {
+		t.Fatalf("expected stale nav to be reset got %v\n", reset)
+	}
+
+	if entries := g.Find(nil, browserk.NavUnvisited, browserk.NavUnvisited, 100); len(entries) != 1 {
+		t.Fatalf("expected stale nav to be unvisited got %d entries\n", len(entries))
+	}
+
+	// every in process navigation is checked, not just the first batch
+	for i := 0; i < 1100; i++ {
+		nav := mock.MakeMockNavi([]byte{1, byte(i >> 8), byte(i)})
+		nav.OriginID = []byte{}
+		if err := g.AddNavigation(nav); err != nil {
+			t.Fatalf("error adding: %s\n", err)
+		}
+	}
+
+	inProcess := len(g.Find(nil, browserk.NavUnvisited, browserk.NavInProcess, 1000))
+	inProcess += len(g.Find(nil, browserk.NavUnvisited, browserk.NavInProcess, 1000))
+	if inProcess != 1101 {
+		t.Fatalf("expected 1101 in process navs got %d\n", inProcess)
+	}
+
+	time.Sleep(time.Millisecond * 10)
+	if reset := g.ResetStaleNavigations(time.Millisecond); len(reset) != 1101 {
+		t.Fatalf("expected all stale navs to be reset got %d\n", len(reset))
+	}
+}
+
 func testGetNavResults(t *testing.T, g browserk.CrawlGrapher) {
 	limit := 5
 	entries := g.Find(nil, browserk.NavUnvisited, browserk.NavUnvisited, int64(limit))
@@ -203,6 +444,7 @@ func TestCrawlAddResults(t *testing.T) {
 
 	for i := 1; i < 3; i++ {
 		navResult := mock.MakeMockResult([]byte{0, byte(i), 2})
+		navResult.AuthChallenges = []*browserk.AuthChallengeEvent{{URL: "http://example.com/admin", Scheme: "basic", Realm: "admin", Answered: true}}
 
 		if err := g.AddResult(navResult); err != nil {
 			t.Fatalf("error adding: %s\n", err)
@@ -216,5 +458,9 @@ func TestCrawlAddResults(t *testing.T) {
 	if res.DOM != "<html>nav result</html>" {
 		t.Fatalf("expected %s got [%s]", "<html>nav result</html>", res.DOM)
 	}
+
+	if len(res.AuthChallenges) != 1 || res.AuthChallenges[0].Realm != "admin" || !res.AuthChallenges[0].Answered {
+		t.Fatalf("expected auth challenge to be stored got %#v\n", res.AuthChallenges)
+	}
 	spew.Dump(res)
 }
@@ -184,6 +184,27 @@ func DecodeNavigationResultItem(item *badger.Item, nav *browserk.NavigationResul
 			nav.StorageEvents = v
 			return err
 		})
+	case "r_blocked":
+		err = item.Value(func(val []byte) error {
+			v := make([]*browserk.BlockedRequestEvent, 0)
+			err := msgpack.Unmarshal(val, &v)
+			nav.BlockedRequests = v
+			return err
+		})
+	case "r_auth_challenges":
+		err = item.Value(func(val []byte) error {
+			v := make([]*browserk.AuthChallengeEvent, 0)
+			err := msgpack.Unmarshal(val, &v)
+			nav.AuthChallenges = v
+			return err
+		})
+	case "r_url_changes":
+		err = item.Value(func(val []byte) error {
+			v := make([]*browserk.URLChangeEvent, 0)
+			err := msgpack.Unmarshal(val, &v)
+			nav.URLChanges = v
+			return err
+		})
 	case "r_caused_load":
 		err = item.Value(func(val []byte) error {
 			var v bool
@@ -265,6 +286,27 @@ func DecodeNavigationItem(item *badger.Item, nav *browserk.Navigation, pred stri
 			nav.Scope = v
 			return err
 		})
+	case "failures":
+		err = item.Value(func(val []byte) error {
+			var v int
+			err := msgpack.Unmarshal(val, &v)
+			nav.Failures = v
+			return err
+		})
+	case "fail_reason":
+		err = item.Value(func(val []byte) error {
+			var v int8
+			err := msgpack.Unmarshal(val, &v)
+			nav.FailReason = browserk.FailureReason(v)
+			return err
+		})
+	case "last_err":
+		err = item.Value(func(val []byte) error {
+			var v string
+			err := msgpack.Unmarshal(val, &v)
+			nav.LastError = v
+			return err
+		})
 	case "action":
 		err = item.Value(func(val []byte) error {
 			v := &browserk.Action{}